@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"uxlyze/analyzer/pkg/report"
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+	"uxlyze/analyzer/pkg/visualdiff"
 )
 
 func HandleVersionRequest(w http.ResponseWriter, r *http.Request) {
@@ -20,10 +23,8 @@ func HandleAnalyzeRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request struct {
-		URL               string `json:"url"`
-		IncludePreview    bool   `json:"includePreview"`
-		IncludePSI        bool   `json:"includePSI"`
-		IncludeAIAnalysis bool   `json:"includeAIAnalysis"`
+		URL string `json:"url"`
+		types.ReportConfig
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&request)
@@ -32,12 +33,13 @@ func HandleAnalyzeRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	report, err := report.Generate(
-		request.URL,
-		request.IncludePreview,
-		request.IncludePSI,
-		request.IncludeAIAnalysis,
-	)
+	options, err := report.OptionsFromConfig(request.ReportConfig)
+	if err != nil {
+		http.Error(w, "Invalid report options: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := report.Generate(request.URL, options)
 
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -49,3 +51,86 @@ func HandleAnalyzeRequest(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(report)
 }
+
+// HandleVisualDiffRequest compares the screenshots of two reports, e.g. the stored baseline
+// and the report of the latest deploy.
+func HandleVisualDiffRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	var request struct {
+		Baseline      *types.Report           `json:"baseline"`
+		Current       *types.Report           `json:"current"`
+		Threshold     float64                 `json:"threshold"`
+		IgnoreRegions map[string][]types.Rect `json:"ignoreRegions"` // Areas in CSS pixels to exclude, per screenshot name
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.Baseline == nil || request.Current == nil {
+		http.Error(w, "Both baseline and current reports are required", http.StatusBadRequest)
+		return
+	}
+
+	regression := visualdiff.CompareReports(request.Baseline, request.Current, visualdiff.ReportOptions{
+		Threshold:     request.Threshold,
+		IgnoreRegions: request.IgnoreRegions,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(regression)
+}
+
+// HandleDesignComparisonRequest captures a page and compares it with a reference design
+// export, returning the deviation heatmap and scored regions.
+func HandleDesignComparisonRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	var request struct {
+		URL           string       `json:"url"`
+		Reference     string       `json:"reference"`     // Base64 PNG or JPEG of the design
+		Width         int64        `json:"width"`         // Viewport width in CSS pixels the design was made for
+		Height        int64        `json:"height"`        // Viewport height in CSS pixels
+		Mobile        bool         `json:"mobile"`        // Emulate a touch device
+		Threshold     float64      `json:"threshold"`     // Perceptual color distance (0-1) tolerated per pixel
+		IgnoreRegions []types.Rect `json:"ignoreRegions"` // Areas in reference pixels to exclude
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.URL == "" || request.Reference == "" {
+		http.Error(w, "Both url and reference are required", http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := report.CompareWithDesign(
+		request.URL,
+		request.Reference,
+		screenshot.Device{Name: "Design", Width: request.Width, Height: request.Height, Mobile: request.Mobile},
+		visualdiff.Options{Threshold: request.Threshold, IgnoreRegions: request.IgnoreRegions},
+	)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Error comparing with design: " + err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comparison)
+}
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"sync/atomic"
 	"time"
+	"uxlyze/analyzer/api"
 	process_worker "uxlyze/analyzer/pkg/process-worker"
 
 	"github.com/gin-gonic/gin"
@@ -164,6 +165,12 @@ func main() {
 	// Handle the job submission endpoint
 	router.POST("/submit-job", SubmitJobHandler)
 
+	// Compare the screenshots of two reports synchronously
+	router.POST("/api/visual-diff", gin.WrapF(api.HandleVisualDiffRequest))
+
+	// Capture a page and compare it with a design export synchronously
+	router.POST("/api/design-comparison", gin.WrapF(api.HandleDesignComparisonRequest))
+
 	// Start the Gin server
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,247 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// accessibilityRule is the static metadata of a rule; the page script only reports rule ids.
+type accessibilityRule struct {
+	Description string
+	WCAG        string
+	Level       string
+	Impact      string
+}
+
+var accessibilityRules = map[string]accessibilityRule{
+	"form-label":            {"Form fields must have an associated label", "1.3.1", "A", "critical"},
+	"aria-valid-role":       {"ARIA role values must be valid", "4.1.2", "A", "serious"},
+	"aria-valid-attr":       {"ARIA attributes must be valid names", "4.1.2", "A", "critical"},
+	"aria-hidden-focusable": {"aria-hidden elements must not be focusable", "4.1.2", "A", "serious"},
+	"landmark-main":         {"Page must have exactly one main landmark", "1.3.1", "A", "moderate"},
+	"landmark-banner":       {"Page should not have more than one banner landmark", "1.3.1", "A", "moderate"},
+	"region":                {"Content should be contained in landmarks", "1.3.1", "A", "moderate"},
+	"link-name":             {"Links must have discernible text", "2.4.4", "A", "serious"},
+	"button-name":           {"Buttons must have discernible text", "4.1.2", "A", "critical"},
+	"heading-order":         {"Heading levels should only increase by one", "1.3.1", "A", "moderate"},
+	"html-lang":             {"<html> element must have a lang attribute", "3.1.1", "A", "serious"},
+	"html-lang-valid":       {"<html> lang attribute must be a valid language tag", "3.1.1", "A", "serious"},
+	"duplicate-id":          {"id attribute values must be unique", "4.1.1", "A", "minor"},
+	"duplicate-id-aria":     {"IDs referenced by ARIA and labels must be unique", "4.1.2", "A", "serious"},
+	"tabindex":              {"Elements should not have a tabindex greater than zero", "2.4.3", "A", "serious"},
+}
+
+type rawViolation struct {
+	Rule     string     `json:"rule"`
+	Message  string     `json:"message"`
+	Selector string     `json:"selector"`
+	Snippet  string     `json:"snippet"`
+	Rect     types.Rect `json:"rect"`
+}
+
+// AnalyzeAccessibility runs a WCAG-oriented rule set inside the page and reports each
+// violation with its success criterion, impact, selector and HTML snippet.
+func AnalyzeAccessibility(ctx context.Context) (*types.AccessibilityAudit, error) {
+	fmt.Println("Analyzing accessibility...")
+	var raw []rawViolation
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const violations = [];
+			function report(rule, el, message) {
+				violations.push({
+					rule,
+					message,
+					selector: el ? cssPath(el) : 'html',
+					snippet: el ? snippetOf(el) : '',
+					rect: el ? rectOf(el) : { x: 0, y: 0, width: 0, height: 0 }
+				});
+			}
+
+			const validRoles = new Set(['alert', 'alertdialog', 'application', 'article', 'banner', 'blockquote',
+				'button', 'caption', 'cell', 'checkbox', 'code', 'columnheader', 'combobox', 'complementary',
+				'contentinfo', 'definition', 'deletion', 'dialog', 'directory', 'document', 'emphasis', 'feed',
+				'figure', 'form', 'generic', 'grid', 'gridcell', 'group', 'heading', 'img', 'insertion', 'link',
+				'list', 'listbox', 'listitem', 'log', 'main', 'marquee', 'math', 'menu', 'menubar', 'menuitem',
+				'menuitemcheckbox', 'menuitemradio', 'meter', 'navigation', 'none', 'note', 'option', 'paragraph',
+				'presentation', 'progressbar', 'radio', 'radiogroup', 'region', 'row', 'rowgroup', 'rowheader',
+				'scrollbar', 'search', 'searchbox', 'separator', 'slider', 'spinbutton', 'status', 'strong',
+				'subscript', 'superscript', 'switch', 'tab', 'table', 'tablist', 'tabpanel', 'term', 'textbox',
+				'time', 'timer', 'toolbar', 'tooltip', 'tree', 'treegrid', 'treeitem', 'doc-abstract',
+				'doc-chapter', 'doc-footnote', 'doc-noteref', 'graphics-document', 'graphics-object', 'graphics-symbol']);
+
+			const validAria = new Set(['aria-activedescendant', 'aria-atomic', 'aria-autocomplete',
+				'aria-braillelabel', 'aria-brailleroledescription', 'aria-busy', 'aria-checked', 'aria-colcount',
+				'aria-colindex', 'aria-colindextext', 'aria-colspan', 'aria-controls', 'aria-current',
+				'aria-describedby', 'aria-description', 'aria-details', 'aria-disabled', 'aria-dropeffect',
+				'aria-errormessage', 'aria-expanded', 'aria-flowto', 'aria-grabbed', 'aria-haspopup', 'aria-hidden',
+				'aria-invalid', 'aria-keyshortcuts', 'aria-label', 'aria-labelledby', 'aria-level', 'aria-live',
+				'aria-modal', 'aria-multiline', 'aria-multiselectable', 'aria-orientation', 'aria-owns',
+				'aria-placeholder', 'aria-posinset', 'aria-pressed', 'aria-readonly', 'aria-relevant',
+				'aria-required', 'aria-roledescription', 'aria-rowcount', 'aria-rowindex', 'aria-rowindextext',
+				'aria-rowspan', 'aria-selected', 'aria-setsize', 'aria-sort', 'aria-valuemax', 'aria-valuemin',
+				'aria-valuenow', 'aria-valuetext']);
+
+			const focusableSelector = 'a[href], area[href], button, input, select, textarea, iframe, ' +
+				'[tabindex], [contenteditable="true"], summary';
+
+			// html-lang / html-lang-valid
+			const lang = (document.documentElement.getAttribute('lang') || '').trim();
+			if (!lang) {
+				report('html-lang', null, 'The <html> element has no lang attribute');
+			} else if (!/^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$/.test(lang)) {
+				report('html-lang-valid', null, 'Invalid lang value "' + lang + '"');
+			}
+
+			// form-label
+			document.querySelectorAll('input, select, textarea').forEach(field => {
+				const type = (field.getAttribute('type') || '').toLowerCase();
+				if (['hidden', 'submit', 'reset', 'button', 'image'].includes(type)) return;
+				if (!isVisible(field)) return;
+				const hasLabel = (field.labels && field.labels.length > 0) ||
+					(field.getAttribute('aria-label') || '').trim() ||
+					(field.getAttribute('aria-labelledby') || '').trim() ||
+					(field.getAttribute('title') || '').trim();
+				if (!hasLabel) {
+					report('form-label', field, 'Form field has no label, aria-label, aria-labelledby or title');
+				}
+			});
+
+			// aria-valid-role / aria-valid-attr / aria-hidden-focusable
+			document.querySelectorAll('*').forEach(el => {
+				const role = el.getAttribute('role');
+				if (role !== null) {
+					const invalid = role.trim().split(/\s+/).filter(r => r && !validRoles.has(r));
+					if (invalid.length > 0 || !role.trim()) {
+						report('aria-valid-role', el, 'Invalid role "' + role + '"');
+					}
+				}
+				for (const attr of el.attributes) {
+					if (attr.name.startsWith('aria-') && !validAria.has(attr.name)) {
+						report('aria-valid-attr', el, 'Unknown ARIA attribute "' + attr.name + '"');
+					}
+				}
+				if (el.getAttribute('aria-hidden') === 'true') {
+					const focusables = [el, ...el.querySelectorAll(focusableSelector)].filter(node =>
+						node.matches(focusableSelector) && node.tabIndex >= 0 && !node.disabled);
+					if (focusables.length > 0) {
+						report('aria-hidden-focusable', el, 'aria-hidden="true" contains ' + focusables.length + ' focusable element(s)');
+					}
+				}
+			});
+
+			// landmark-main / landmark-banner / region
+			const mains = document.querySelectorAll('main, [role="main"]');
+			if (mains.length === 0) {
+				report('landmark-main', null, 'No <main> or role="main" landmark found');
+			} else if (mains.length > 1) {
+				report('landmark-main', mains[1], mains.length + ' main landmarks found');
+			}
+			const banners = Array.from(document.querySelectorAll('header, [role="banner"]'))
+				.filter(el => el.getAttribute('role') === 'banner' || !el.closest('article, aside, main, nav, section'));
+			if (banners.length > 1) {
+				report('landmark-banner', banners[1], banners.length + ' banner landmarks found');
+			}
+			const landmarkSelector = 'header, nav, main, footer, aside, form[aria-label], section[aria-label], ' +
+				'[role="banner"], [role="navigation"], [role="main"], [role="contentinfo"], ' +
+				'[role="complementary"], [role="region"], [role="search"], [role="form"]';
+			Array.from(document.body ? document.body.children : []).forEach(el => {
+				if (['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'LINK', 'META'].includes(el.tagName)) return;
+				if (el.matches(landmarkSelector) || el.querySelector(landmarkSelector)) return;
+				if (!isVisible(el) || !(el.innerText || '').trim()) return;
+				report('region', el, 'Content is not contained in any landmark');
+			});
+
+			// link-name / button-name
+			document.querySelectorAll('a[href]').forEach(link => {
+				if (!isVisible(link)) return;
+				if (!accessibleName(link)) report('link-name', link, 'Link has no accessible name');
+			});
+			document.querySelectorAll('button, [role="button"], input[type="button"], input[type="submit"], input[type="reset"]').forEach(button => {
+				if (!isVisible(button)) return;
+				if (!accessibleName(button)) report('button-name', button, 'Button has no accessible name');
+			});
+
+			// heading-order
+			let previousLevel = 0;
+			document.querySelectorAll('h1, h2, h3, h4, h5, h6, [role="heading"]').forEach(heading => {
+				const level = heading.getAttribute('role') === 'heading'
+					? parseInt(heading.getAttribute('aria-level') || '2', 10)
+					: parseInt(heading.tagName.substring(1), 10);
+				if (previousLevel > 0 && level > previousLevel + 1) {
+					report('heading-order', heading, 'Heading level jumps from h' + previousLevel + ' to h' + level);
+				}
+				previousLevel = level;
+			});
+
+			// duplicate-id / duplicate-id-aria
+			const ids = {};
+			document.querySelectorAll('[id]').forEach(el => {
+				if (!el.id) return;
+				(ids[el.id] = ids[el.id] || []).push(el);
+			});
+			const referencedIds = new Set();
+			document.querySelectorAll('[aria-labelledby], [aria-describedby], [aria-controls], [aria-owns], label[for]').forEach(el => {
+				['aria-labelledby', 'aria-describedby', 'aria-controls', 'aria-owns', 'for'].forEach(attr => {
+					(el.getAttribute(attr) || '').split(/\s+/).filter(Boolean).forEach(id => referencedIds.add(id));
+				});
+			});
+			Object.keys(ids).forEach(id => {
+				if (ids[id].length < 2) return;
+				const rule = referencedIds.has(id) ? 'duplicate-id-aria' : 'duplicate-id';
+				report(rule, ids[id][1], 'id "' + id + '" is used ' + ids[id].length + ' times');
+			});
+
+			// tabindex
+			document.querySelectorAll('[tabindex]').forEach(el => {
+				const value = parseInt(el.getAttribute('tabindex'), 10);
+				if (value > 0) report('tabindex', el, 'tabindex="' + value + '" overrides the natural tab order');
+			});
+
+			return violations;
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAccessibilityAudit(raw), nil
+}
+
+func buildAccessibilityAudit(raw []rawViolation) *types.AccessibilityAudit {
+	audit := &types.AccessibilityAudit{
+		RulesChecked: len(accessibilityRules),
+		ByImpact:     make(map[string]int),
+		Violations:   make([]types.AccessibilityViolation, 0, len(raw)),
+	}
+
+	failedRules := make(map[string]bool)
+	for _, v := range raw {
+		rule, ok := accessibilityRules[v.Rule]
+		if !ok {
+			continue
+		}
+		failedRules[v.Rule] = true
+		audit.ByImpact[rule.Impact]++
+		audit.Violations = append(audit.Violations, types.AccessibilityViolation{
+			Rule:        v.Rule,
+			Description: rule.Description,
+			WCAG:        rule.WCAG,
+			Level:       rule.Level,
+			Impact:      rule.Impact,
+			Message:     v.Message,
+			Selector:    v.Selector,
+			Snippet:     v.Snippet,
+			Rect:        v.Rect,
+		})
+	}
+	audit.RulesFailed = len(failedRules)
+
+	return audit
+}
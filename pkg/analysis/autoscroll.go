@@ -0,0 +1,185 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Auto-scroll limits. The height and step caps end infinite scroll.
+const (
+	networkQuietTime = 500 * time.Millisecond // No requests in flight for this long counts as quiet
+	maxNetworkWait   = 3 * time.Second        // Longest wait for quiet after a step
+	maxScrollSteps   = 150
+	maxScrollHeight  = 30000 // CSS pixels
+)
+
+// Auto-scroll time budgets. Callers add them to their own timeout so scrolling slow pages
+// does not eat into the time of the analyzers.
+const (
+	AutoScrollBudget   = 45 * time.Second // The pass before analysis
+	AutoRescrollBudget = 15 * time.Second // A repeat pass after a reload, mostly served from cache
+)
+
+type scrollState struct {
+	Y        float64 `json:"y"`
+	Viewport float64 `json:"viewport"`
+	Height   float64 `json:"height"`
+}
+
+// scrollStepJS scrolls down by 80% of the viewport, so consecutive views overlap, without
+// smooth scrolling and reports the position.
+const scrollStepJS = `
+	(function() {
+		window.scrollBy({top: Math.round(window.innerHeight * 0.8), behavior: 'instant'});
+		return {
+			y: window.scrollY,
+			viewport: window.innerHeight,
+			height: document.documentElement.scrollHeight
+		};
+	})()
+`
+
+// scrollTopJS returns to the top and reports the position.
+const scrollTopJS = `
+	(function() {
+		window.scrollTo({top: 0, behavior: 'instant'});
+		return {
+			y: window.scrollY,
+			viewport: window.innerHeight,
+			height: document.documentElement.scrollHeight
+		};
+	})()
+`
+
+// networkTracker counts the requests in flight from the network events of the page.
+type networkTracker struct {
+	mu       sync.Mutex
+	inflight map[network.RequestID]bool
+	last     time.Time
+	started  int
+}
+
+func newNetworkTracker(ctx context.Context) *networkTracker {
+	t := &networkTracker{inflight: make(map[network.RequestID]bool), last: time.Now()}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			// Long-lived connections never finish and would keep the page from going quiet
+			if e.Type == network.ResourceTypeWebSocket || e.Type == network.ResourceTypeEventSource {
+				return
+			}
+			t.inflight[e.RequestID] = true
+			t.started++
+			t.last = time.Now()
+		case *network.EventLoadingFinished:
+			delete(t.inflight, e.RequestID)
+			t.last = time.Now()
+		case *network.EventLoadingFailed:
+			delete(t.inflight, e.RequestID)
+			t.last = time.Now()
+		}
+	})
+	return t
+}
+
+// waitQuiet blocks until no request has been in flight for networkQuietTime, or at most
+// maxNetworkWait.
+func (t *networkTracker) waitQuiet(ctx context.Context) error {
+	deadline := time.Now().Add(maxNetworkWait)
+	for time.Now().Before(deadline) {
+		t.mu.Lock()
+		quiet := len(t.inflight) == 0 && time.Since(t.last) >= networkQuietTime
+		t.mu.Unlock()
+		if quiet {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return nil
+}
+
+func (t *networkTracker) requests() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.started
+}
+
+// AutoScroll scrolls through the page step by step so lazy-loaded images and sections load
+// before the analyzers and screenshots run. After every step it waits for the network to go
+// quiet; pages that keep growing are scrolled until the height or step cap is reached or the
+// budget is spent. It finishes back at the top of the page.
+func AutoScroll(ctx context.Context, budget time.Duration) (*types.AutoScrollResult, error) {
+	fmt.Println("Auto-scrolling to load lazy content...")
+	start := time.Now()
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tracker := newNetworkTracker(listenCtx)
+
+	var state scrollState
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(scrollTopJS, &state)); err != nil {
+		return nil, err
+	}
+	result := &types.AutoScrollResult{InitialHeight: state.Height, StoppedBy: "end"}
+
+	previousY := state.Y
+	for {
+		if result.Steps >= maxScrollSteps {
+			result.StoppedBy = "maxSteps"
+			break
+		}
+		if time.Since(start) >= budget {
+			result.StoppedBy = "timeout"
+			break
+		}
+
+		if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(scrollStepJS, &state)); err != nil {
+			return result, err
+		}
+		result.Steps++
+		if err := tracker.waitQuiet(ctx); err != nil {
+			return result, err
+		}
+
+		// Read the height again: the step may have loaded more content
+		if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`document.documentElement.scrollHeight`, &state.Height)); err != nil {
+			return result, err
+		}
+		// Stop at the bottom, or when the window does not scroll at all, e.g. because the
+		// page scrolls inside a container
+		if state.Y+state.Viewport >= state.Height-1 || state.Y <= previousY {
+			break
+		}
+		previousY = state.Y
+		if state.Y+state.Viewport >= maxScrollHeight {
+			result.StoppedBy = "maxHeight"
+			break
+		}
+	}
+	result.Capped = result.StoppedBy != "end"
+
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(scrollTopJS, &state)); err != nil {
+		return result, err
+	}
+	if err := tracker.waitQuiet(ctx); err != nil {
+		return result, err
+	}
+
+	result.FinalHeight = state.Height
+	result.Requests = tracker.requests()
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
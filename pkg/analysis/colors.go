@@ -0,0 +1,278 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rgba is a color with 0-255 channels and 0-1 alpha, as reported by getComputedStyle.
+type rgba struct {
+	R, G, B float64
+	A       float64
+}
+
+var white = rgba{255, 255, 255, 1}
+
+// namedColors covers the basic CSS color keywords, which is what people (and Gemini)
+// usually mean when they name a color.
+var namedColors = map[string]string{
+	"black": "#000000", "silver": "#c0c0c0", "gray": "#808080", "grey": "#808080",
+	"white": "#ffffff", "maroon": "#800000", "red": "#ff0000", "purple": "#800080",
+	"fuchsia": "#ff00ff", "magenta": "#ff00ff", "green": "#008000", "lime": "#00ff00",
+	"olive": "#808000", "yellow": "#ffff00", "navy": "#000080", "blue": "#0000ff",
+	"teal": "#008080", "aqua": "#00ffff", "cyan": "#00ffff", "orange": "#ffa500",
+	"pink": "#ffc0cb", "brown": "#a52a2a", "gold": "#ffd700", "indigo": "#4b0082",
+	"violet": "#ee82ee", "beige": "#f5f5dc", "crimson": "#dc143c", "coral": "#ff7f50",
+}
+
+// parseCSSColor parses the rgb()/rgba() strings returned by getComputedStyle, plus hex
+// notation, basic color keywords and "transparent". ok is false for anything else
+// (e.g. color(), currentcolor).
+func parseCSSColor(value string) (c rgba, ok bool) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	switch {
+	case value == "transparent":
+		return rgba{0, 0, 0, 0}, true
+	case strings.HasPrefix(value, "#"):
+		return parseHexColor(value)
+	case strings.HasPrefix(value, "rgb"):
+	default:
+		if named, ok := namedColors[value]; ok {
+			return parseHexColor(named)
+		}
+		return rgba{}, false
+	}
+
+	open := strings.IndexByte(value, '(')
+	end := strings.LastIndexByte(value, ')')
+	if open < 0 || end < open {
+		return rgba{}, false
+	}
+	fields := strings.FieldsFunc(value[open+1:end], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '/'
+	})
+	if len(fields) < 3 {
+		return rgba{}, false
+	}
+
+	channels := make([]float64, 4)
+	channels[3] = 1
+	for i := 0; i < len(fields) && i < 4; i++ {
+		field := fields[i]
+		percent := strings.HasSuffix(field, "%")
+		n, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+		if err != nil {
+			return rgba{}, false
+		}
+		switch {
+		case percent && i < 3:
+			n = n * 255 / 100
+		case percent:
+			n = n / 100
+		}
+		channels[i] = n
+	}
+	return rgba{channels[0], channels[1], channels[2], channels[3]}, true
+}
+
+func parseHexColor(value string) (rgba, bool) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	if len(hex) != 6 && len(hex) != 8 {
+		return rgba{}, false
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgba{}, false
+	}
+	if len(hex) == 6 {
+		n = n<<8 | 0xff
+	}
+	return rgba{
+		R: float64(n >> 24 & 0xff),
+		G: float64(n >> 16 & 0xff),
+		B: float64(n >> 8 & 0xff),
+		A: float64(n&0xff) / 255,
+	}, true
+}
+
+// over composites c on top of an opaque backdrop.
+func (c rgba) over(backdrop rgba) rgba {
+	return rgba{
+		R: c.R*c.A + backdrop.R*(1-c.A),
+		G: c.G*c.A + backdrop.G*(1-c.A),
+		B: c.B*c.A + backdrop.B*(1-c.A),
+		A: 1,
+	}
+}
+
+// hex formats the color as #rrggbb, ignoring alpha.
+func (c rgba) hex() string {
+	clamp := func(v float64) int {
+		return int(math.Round(math.Max(0, math.Min(255, v))))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", clamp(c.R), clamp(c.G), clamp(c.B))
+}
+
+// relativeLuminance implements the WCAG 2.x relative luminance formula.
+func (c rgba) relativeLuminance() float64 {
+	linear := func(channel float64) float64 {
+		s := channel / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// contrastRatio returns the WCAG contrast ratio (1-21) between two opaque colors.
+func contrastRatio(a, b rgba) float64 {
+	la, lb := a.relativeLuminance(), b.relativeLuminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// mix linearly interpolates from c towards target by t (0-1).
+func (c rgba) mix(target rgba, t float64) rgba {
+	return rgba{
+		R: c.R + (target.R-c.R)*t,
+		G: c.G + (target.G-c.G)*t,
+		B: c.B + (target.B-c.B)*t,
+		A: 1,
+	}
+}
+
+// nearestPassingColor finds the smallest shift of fg towards black or white that reaches
+// the required contrast against bg. It returns false if neither direction can pass.
+func nearestPassingColor(fg, bg rgba, required float64) (rgba, bool) {
+	best, found := rgba{}, false
+	bestT := math.Inf(1)
+	for _, target := range []rgba{{0, 0, 0, 1}, white} {
+		if contrastRatio(target, bg) < required {
+			continue
+		}
+		lo, hi := 0.0, 1.0
+		for i := 0; i < 20; i++ {
+			mid := (lo + hi) / 2
+			if contrastRatio(fg.mix(target, mid).rounded(), bg) >= required {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		if hi < bestT {
+			bestT, best, found = hi, fg.mix(target, hi).rounded(), true
+		}
+	}
+	return best, found
+}
+
+// rounded snaps the channels to whole 8-bit values.
+func (c rgba) rounded() rgba {
+	return rgba{math.Round(c.R), math.Round(c.G), math.Round(c.B), c.A}
+}
+
+// lab converts the color to CIELAB (D65 white point), ignoring alpha.
+func (c rgba) lab() [3]float64 {
+	linear := func(channel float64) float64 {
+		s := channel / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+	x := (0.4124*r + 0.3576*g + 0.1805*b) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := (0.0193*r + 0.1192*g + 0.9505*b) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// chroma is the colorfulness of a Lab color; grays are close to 0.
+func chroma(lab [3]float64) float64 {
+	return math.Hypot(lab[1], lab[2])
+}
+
+// deltaE2000 is the CIEDE2000 perceptual distance between two Lab colors.
+// Around 2 is barely noticeable, above 10 the colors read as different.
+func deltaE2000(lab1, lab2 [3]float64) float64 {
+	const deg = math.Pi / 180
+	l1, a1, b1 := lab1[0], lab1[1], lab1[2]
+	l2, a2, b2 := lab2[0], lab2[1], lab2[2]
+
+	cBar := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	g := 0.5 * (1 - math.Sqrt(math.Pow(cBar, 7)/(math.Pow(cBar, 7)+math.Pow(25, 7))))
+	a1p, a2p := a1*(1+g), a2*(1+g)
+	c1p, c2p := math.Hypot(a1p, b1), math.Hypot(a2p, b2)
+
+	hue := func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		h := math.Atan2(b, a) / deg
+		if h < 0 {
+			h += 360
+		}
+		return h
+	}
+	h1p, h2p := hue(b1, a1p), hue(b2, a2p)
+
+	dLp := l2 - l1
+	dCp := c2p - c1p
+	dhp := 0.0
+	if c1p*c2p != 0 {
+		dhp = h2p - h1p
+		switch {
+		case dhp > 180:
+			dhp -= 360
+		case dhp < -180:
+			dhp += 360
+		}
+	}
+	dHp := 2 * math.Sqrt(c1p*c2p) * math.Sin(dhp/2*deg)
+
+	lBarP := (l1 + l2) / 2
+	cBarP := (c1p + c2p) / 2
+	hBarP := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hBarP /= 2
+		case h1p+h2p < 360:
+			hBarP = (hBarP + 360) / 2
+		default:
+			hBarP = (hBarP - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos((hBarP-30)*deg) + 0.24*math.Cos(2*hBarP*deg) +
+		0.32*math.Cos((3*hBarP+6)*deg) - 0.20*math.Cos((4*hBarP-63)*deg)
+	dTheta := 30 * math.Exp(-math.Pow((hBarP-275)/25, 2))
+	rc := 2 * math.Sqrt(math.Pow(cBarP, 7)/(math.Pow(cBarP, 7)+math.Pow(25, 7)))
+	sl := 1 + 0.015*math.Pow(lBarP-50, 2)/math.Sqrt(20+math.Pow(lBarP-50, 2))
+	sc := 1 + 0.045*cBarP
+	sh := 1 + 0.015*cBarP*t
+	rt := -math.Sin(2*dTheta*deg) * rc
+
+	return math.Sqrt(math.Pow(dLp/sl, 2) + math.Pow(dCp/sc, 2) + math.Pow(dHp/sh, 2) +
+		rt*(dCp/sc)*(dHp/sh))
+}
@@ -0,0 +1,197 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// WCAG 2.x contrast thresholds for normal and large text.
+const (
+	contrastAANormal  = 4.5
+	contrastAALarge   = 3.0
+	contrastAAANormal = 7.0
+	contrastAAALarge  = 4.5
+)
+
+type rawTextElement struct {
+	Selector   string  `json:"selector"`
+	Text       string  `json:"text"`
+	Color      string  `json:"color"`
+	Opacity    float64 `json:"opacity"`
+	FontSize   float64 `json:"fontSize"`
+	FontWeight int     `json:"fontWeight"`
+	// Backgrounds lists background colors from the element up to the root, stopping at the
+	// first opaque one.
+	Backgrounds        []string   `json:"backgrounds"`
+	HasBackgroundImage bool       `json:"hasBackgroundImage"`
+	Rect               types.Rect `json:"rect"`
+}
+
+// AnalyzeContrast resolves the effective foreground and background color of every visible
+// text element and checks the WCAG contrast ratio against AA/AAA thresholds.
+func AnalyzeContrast(ctx context.Context) (*types.ContrastAudit, error) {
+	fmt.Println("Analyzing color contrast...")
+	var raw []rawTextElement
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const seen = new Set();
+			const elements = [];
+			const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT, {
+				acceptNode: node => node.textContent.trim() ? NodeFilter.FILTER_ACCEPT : NodeFilter.FILTER_REJECT
+			});
+
+			while (walker.nextNode()) {
+				const el = walker.currentNode.parentElement;
+				if (!el || seen.has(el)) continue;
+				seen.add(el);
+				if (['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'OPTION'].includes(el.tagName)) continue;
+				if (!isVisible(el)) continue;
+
+				// Text under a fully transparent ancestor, e.g. a closed menu, is not shown
+				let opacity = 1;
+				for (let node = el; node; node = node.parentElement) {
+					const value = parseFloat(window.getComputedStyle(node).opacity);
+					opacity *= isNaN(value) ? 1 : value;
+				}
+				if (opacity === 0) continue;
+
+				const style = window.getComputedStyle(el);
+				const backgrounds = [];
+				let hasBackgroundImage = false;
+				for (let node = el; node; node = node.parentElement) {
+					const nodeStyle = window.getComputedStyle(node);
+					if (nodeStyle.backgroundImage && nodeStyle.backgroundImage !== 'none') {
+						hasBackgroundImage = true;
+						break;
+					}
+					const bg = nodeStyle.backgroundColor;
+					if (bg && bg !== 'transparent' && bg !== 'rgba(0, 0, 0, 0)') {
+						backgrounds.push(bg);
+						const alpha = bg.startsWith('rgba') ? parseFloat(bg.split(',')[3]) : 1;
+						if (alpha >= 1) break;
+					}
+				}
+
+				const directText = Array.from(el.childNodes)
+					.filter(node => node.nodeType === Node.TEXT_NODE)
+					.map(node => node.textContent.trim())
+					.join(' ')
+					.trim();
+
+				elements.push({
+					selector: cssPath(el),
+					text: directText.substring(0, 80),
+					color: style.color,
+					opacity,
+					fontSize: parseFloat(style.fontSize) || 16,
+					fontWeight: parseInt(style.fontWeight, 10) || 400,
+					backgrounds,
+					hasBackgroundImage,
+					rect: rectOf(el)
+				});
+			}
+
+			return elements;
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildContrastAudit(raw), nil
+}
+
+func buildContrastAudit(raw []rawTextElement) *types.ContrastAudit {
+	audit := &types.ContrastAudit{TextElements: len(raw), Issues: []types.ContrastIssue{}}
+
+	for _, el := range raw {
+		if el.HasBackgroundImage {
+			audit.Unresolved++
+			continue
+		}
+
+		fg, ok := parseCSSColor(el.Color)
+		if !ok {
+			audit.Unresolved++
+			continue
+		}
+		bg, ok := effectiveBackground(el.Backgrounds)
+		if !ok {
+			audit.Unresolved++
+			continue
+		}
+		fg.A *= el.Opacity
+		fg = fg.over(bg)
+
+		large := isLargeText(el.FontSize, el.FontWeight)
+		requiredAA, requiredAAA := contrastAANormal, contrastAAANormal
+		if large {
+			requiredAA, requiredAAA = contrastAALarge, contrastAAALarge
+		}
+
+		ratio := contrastRatio(fg, bg)
+		if ratio >= requiredAAA {
+			continue
+		}
+
+		passesAA := ratio >= requiredAA
+		if !passesAA {
+			audit.FailingAA++
+		}
+		audit.FailingAAA++
+
+		target := requiredAA
+		if passesAA {
+			target = requiredAAA
+		}
+		suggestion := ""
+		if c, ok := nearestPassingColor(fg, bg, target); ok {
+			suggestion = c.hex()
+		}
+
+		audit.Issues = append(audit.Issues, types.ContrastIssue{
+			Selector:       el.Selector,
+			Text:           el.Text,
+			Foreground:     fg.hex(),
+			Background:     bg.hex(),
+			Ratio:          math.Round(ratio*100) / 100,
+			FontSize:       el.FontSize,
+			FontWeight:     el.FontWeight,
+			LargeText:      large,
+			RequiredAA:     requiredAA,
+			RequiredAAA:    requiredAAA,
+			PassesAA:       passesAA,
+			SuggestedColor: suggestion,
+			Rect:           el.Rect,
+		})
+	}
+
+	return audit
+}
+
+// effectiveBackground composites the collected background layers (innermost first) over
+// the white canvas.
+func effectiveBackground(layers []string) (rgba, bool) {
+	bg := white
+	for i := len(layers) - 1; i >= 0; i-- {
+		c, ok := parseCSSColor(layers[i])
+		if !ok {
+			return rgba{}, false
+		}
+		bg = c.over(bg)
+	}
+	return bg, true
+}
+
+// isLargeText applies the WCAG definition: at least 18pt, or 14pt bold (24px / 18.66px).
+func isLargeText(fontSize float64, fontWeight int) bool {
+	return fontSize >= 24 || (fontSize >= 18.66 && fontWeight >= 700)
+}
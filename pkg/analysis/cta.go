@@ -0,0 +1,427 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// ctaAttr marks collected CTAs so their hover and focus states can be forced later on.
+const ctaAttr = "data-uxlyze-cta"
+
+// maxCTAs caps the CTAs kept in the report; maxCTAProbes caps the hover/focus checks,
+// which take a few protocol round trips each.
+const (
+	maxCTAs      = 60
+	maxCTAProbes = 8
+)
+
+// primaryProminence is the share of the highest prominence a filled CTA needs to count as primary.
+const primaryProminence = 0.75
+
+// vagueCTALabels say nothing about what happens after the click.
+var vagueCTALabels = map[string]bool{
+	"click here": true, "click": true, "here": true, "submit": true, "go": true, "ok": true,
+	"more": true, "learn more": true, "read more": true, "see more": true, "find out more": true,
+	"continue": true, "next": true, "send": true, "button": true, "link": true, "details": true,
+}
+
+var ctaLabelTrim = regexp.MustCompile(`[^\p{L}\p{N}\s]+`)
+
+type rawCTA struct {
+	ID                 string `json:"id"`
+	Selector           string `json:"selector"`
+	Tag                string `json:"tag"`
+	Label              string `json:"label"`
+	Background         string `json:"background"`
+	HasBackgroundImage bool   `json:"hasBackgroundImage"`
+	Color              string `json:"color"`
+	// Surroundings lists background colors from the parent up to the root, stopping at the
+	// first opaque one.
+	Surroundings []string   `json:"surroundings"`
+	Rect         types.Rect `json:"rect"`
+}
+
+type rawCTAPage struct {
+	Fold float64  `json:"fold"`
+	CTAs []rawCTA `json:"ctas"`
+}
+
+// ctaFinderJS defines findCTAs(), which returns the visible buttons and button-like links in
+// document order, skipping CTAs nested in another one. It is meant to be concatenated after
+// domHelpersJS.
+const ctaFinderJS = `
+	function isTransparentColor(bg) {
+		return !bg || bg === 'transparent' || bg === 'rgba(0, 0, 0, 0)';
+	}
+
+	// Links count when their class says so or when they are padded and filled or outlined
+	function isButtonLike(el, style) {
+		if (/(^|[\s_-])(btn|button|cta)([\s_-]|$)/i.test(el.getAttribute('class') || '')) return true;
+		const padded = parseFloat(style.paddingLeft) >= 8 && parseFloat(style.paddingTop) >= 4;
+		const bordered = parseFloat(style.borderTopWidth) > 0 && parseFloat(style.borderTopLeftRadius) > 0;
+		return padded && (!isTransparentColor(style.backgroundColor) || style.backgroundImage.includes('gradient') || bordered);
+	}
+
+	function findCTAs() {
+		const found = [];
+		document.querySelectorAll('button, input[type="submit"], input[type="button"], [role="button"], a[href]').forEach(el => {
+			if (!isVisible(el) || el.disabled || found.some(other => other.contains(el))) return;
+			if (el.tagName === 'A' && el.getAttribute('role') !== 'button' && !isButtonLike(el, window.getComputedStyle(el))) return;
+			found.push(el);
+		});
+		return found;
+	}
+`
+
+// ctaSetupJS tags every CTA and remembers the style properties that are compared after
+// forcing :hover or :focus on it.
+const ctaSetupJS = `
+(function() {` + domHelpersJS + ctaFinderJS + `
+	const stateProps = ['backgroundColor', 'color', 'borderTopColor', 'borderBottomColor', 'boxShadow',
+		'outlineStyle', 'outlineColor', 'textDecorationLine', 'opacity', 'transform', 'filter', 'backgroundImage'];
+	window.__uxlyzeCtaProps = stateProps;
+
+	// Forced states must be read without waiting for transitions to finish.
+	const noTransition = document.createElement('style');
+	noTransition.id = 'uxlyze-cta-no-transition';
+	noTransition.textContent = '[` + ctaAttr + `] { transition: none !important; }';
+	document.head.appendChild(noTransition);
+
+	const ctas = [];
+	findCTAs().forEach(el => {
+		const style = window.getComputedStyle(el);
+		const id = String(ctas.length);
+		el.setAttribute('` + ctaAttr + `', id);
+		const base = {};
+		stateProps.forEach(prop => base[prop] = style[prop]);
+		el.__uxlyzeCtaBase = base;
+
+		const surroundings = [];
+		for (let node = el.parentElement; node; node = node.parentElement) {
+			const bg = window.getComputedStyle(node).backgroundColor;
+			if (isTransparentColor(bg)) continue;
+			surroundings.push(bg);
+			const alpha = bg.startsWith('rgba') ? parseFloat(bg.split(',')[3]) : 1;
+			if (alpha >= 1) break;
+		}
+
+		ctas.push({
+			id,
+			selector: cssPath(el),
+			tag: el.tagName.toLowerCase(),
+			label: (accessibleName(el) || el.value || '').replace(/\s+/g, ' ').trim().substring(0, 80),
+			background: style.backgroundColor,
+			hasBackgroundImage: style.backgroundImage.includes('gradient'),
+			color: style.color,
+			surroundings,
+			rect: rectOf(el)
+		});
+	});
+	return { fold: window.innerHeight, ctas };
+})()
+`
+
+const ctaCleanupJS = `
+(function() {
+	document.querySelectorAll('[` + ctaAttr + `]').forEach(el => {
+		el.removeAttribute('` + ctaAttr + `');
+		delete el.__uxlyzeCtaBase;
+	});
+	const noTransition = document.getElementById('uxlyze-cta-no-transition');
+	if (noTransition) noTransition.remove();
+	return true;
+})()
+`
+
+// AnalyzeCTAs finds buttons and button-like links, ranks them by prominence (size, contrast
+// with their surroundings and position), and checks fold placement, label clarity, competing
+// primary CTAs and whether the most prominent ones react to hover and focus.
+func AnalyzeCTAs(ctx context.Context) (*types.CTAAudit, error) {
+	fmt.Println("Analyzing calls to action...")
+	var raw rawCTAPage
+	err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(ctaSetupJS, &raw))
+	defer chromedp.Run(ctx, chromedp.EvaluateAsDevTools(ctaCleanupJS, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	audit, ids := buildCTAAudit(raw)
+	for i := range audit.CTAs {
+		if i >= maxCTAProbes {
+			break
+		}
+		cta := &audit.CTAs[i]
+		hover, focus, err := probeCTAStates(ctx, ids[i])
+		if err != nil {
+			continue
+		}
+		cta.Probed = true
+		cta.HoverChange = hover
+		cta.FocusChange = focus
+	}
+	scoreCTAAudit(audit)
+	return audit, nil
+}
+
+// probeCTAStates forces :hover and then :focus/:focus-visible on the tagged CTA through the
+// CSS domain and reports which style properties changed.
+func probeCTAStates(ctx context.Context, id string) (hover, focus []string, err error) {
+	err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var obj *runtime.RemoteObject
+		if err := chromedp.EvaluateAsDevTools(`document.querySelector('[`+ctaAttr+`="`+id+`"]')`, &obj).Do(ctx); err != nil {
+			return err
+		}
+		if obj.ObjectID == "" {
+			return fmt.Errorf("cta %s not found", id)
+		}
+		defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+
+		nodeID, err := dom.RequestNode(obj.ObjectID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if hover, err = forcedStateChanges(ctx, nodeID, id, "hover"); err != nil {
+			return err
+		}
+		focus, err = forcedStateChanges(ctx, nodeID, id, "focus", "focus-visible")
+		return err
+	}))
+	return hover, focus, err
+}
+
+func forcedStateChanges(ctx context.Context, nodeID cdp.NodeID, id string, states ...string) ([]string, error) {
+	if err := css.ForcePseudoState(nodeID, states).Do(ctx); err != nil {
+		return nil, err
+	}
+	defer css.ForcePseudoState(nodeID, []string{}).Do(ctx)
+
+	changes := []string{}
+	err := chromedp.EvaluateAsDevTools(`
+	(function() {
+		const el = document.querySelector('[`+ctaAttr+`="`+id+`"]');
+		if (!el || !el.__uxlyzeCtaBase) return [];
+		const style = window.getComputedStyle(el);
+		return window.__uxlyzeCtaProps.filter(prop => style[prop] !== el.__uxlyzeCtaBase[prop]);
+	})()
+	`, &changes).Do(ctx)
+	return changes, err
+}
+
+// buildCTAAudit ranks the CTAs by prominence and classifies them. The returned ids are the
+// data attribute values of the ranked CTAs, in the same order.
+func buildCTAAudit(raw rawCTAPage) (*types.CTAAudit, []string) {
+	audit := &types.CTAAudit{
+		Total:      len(raw.CTAs),
+		FoldHeight: raw.Fold,
+		Competing:  []types.CTAGroup{},
+		CTAs:       []types.CTAInfo{},
+		Issues:     []string{},
+	}
+	if len(raw.CTAs) == 0 {
+		return audit, nil
+	}
+
+	maxArea := 0.0
+	for _, r := range raw.CTAs {
+		maxArea = math.Max(maxArea, r.Rect.Width*r.Rect.Height)
+	}
+
+	ranked := make([]int, 0, len(raw.CTAs))
+	for i, r := range raw.CTAs {
+		ranked = append(ranked, i)
+		surroundings, ok := effectiveBackground(r.Surroundings)
+		if !ok {
+			surroundings = white
+		}
+		cta := types.CTAInfo{
+			Label:       r.Label,
+			Selector:    r.Selector,
+			Tag:         r.Tag,
+			VagueLabel:  isVagueCTALabel(r.Label),
+			AboveFold:   raw.Fold > 0 && r.Rect.Y+r.Rect.Height/2 < raw.Fold,
+			HoverChange: []string{},
+			FocusChange: []string{},
+			Rect:        r.Rect,
+		}
+
+		// Filled buttons stand out through their fill, links only through their text.
+		emphasis := 0.0
+		if bg, ok := parseCSSColor(r.Background); ok && bg.A > 0 {
+			cta.Filled = true
+			cta.Contrast = contrastRatio(bg.over(surroundings), surroundings)
+			emphasis = math.Min(cta.Contrast/contrastAALarge, 1)
+		}
+		if r.HasBackgroundImage {
+			cta.Filled = true
+			emphasis = math.Max(emphasis, 0.8)
+		}
+		if !cta.Filled {
+			if fg, ok := parseCSSColor(r.Color); ok {
+				cta.Contrast = contrastRatio(fg.over(surroundings), surroundings)
+			}
+			emphasis = math.Min(cta.Contrast/contrastAANormal, 1) * 0.5
+		}
+		cta.Contrast = math.Round(cta.Contrast*100) / 100
+
+		size := 0.0
+		if maxArea > 0 {
+			size = math.Sqrt(r.Rect.Width * r.Rect.Height / maxArea)
+		}
+		position := 1.0
+		if !cta.AboveFold && raw.Fold > 0 {
+			position = math.Max(0, 1-(r.Rect.Y-raw.Fold)/(2*raw.Fold))
+		}
+		cta.Prominence = math.Round(100*(0.3*size+0.45*emphasis+0.25*position)*10) / 10
+
+		audit.CTAs = append(audit.CTAs, cta)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool { return audit.CTAs[ranked[i]].Prominence > audit.CTAs[ranked[j]].Prominence })
+	sorted := make([]types.CTAInfo, len(ranked))
+	ids := make([]string, len(ranked))
+	for i, index := range ranked {
+		sorted[i] = audit.CTAs[index]
+		ids[i] = raw.CTAs[index].ID
+	}
+	audit.CTAs = sorted
+
+	topFilled := 0.0
+	for _, cta := range audit.CTAs {
+		if cta.Filled {
+			topFilled = math.Max(topFilled, cta.Prominence)
+		}
+	}
+	for i := range audit.CTAs {
+		cta := &audit.CTAs[i]
+		// Without any filled button the single most prominent link is the primary CTA.
+		cta.Primary = (cta.Filled && cta.Prominence >= topFilled*primaryProminence) || (topFilled == 0 && i == 0)
+		if cta.Primary {
+			audit.PrimaryCount++
+			audit.PrimaryAbove = audit.PrimaryAbove || cta.AboveFold
+		}
+		if cta.VagueLabel {
+			audit.VagueLabels++
+		}
+	}
+
+	audit.Competing = competingCTAs(audit.CTAs, raw.Fold)
+
+	if len(audit.CTAs) > maxCTAs {
+		audit.CTAs = audit.CTAs[:maxCTAs]
+		ids = ids[:maxCTAs]
+	}
+	return audit, ids
+}
+
+// competingCTAs groups primary CTAs by screen height and reports screens showing more than
+// two different primary actions.
+func competingCTAs(ctas []types.CTAInfo, fold float64) []types.CTAGroup {
+	groups := []types.CTAGroup{}
+	if fold <= 0 {
+		return groups
+	}
+	bands := make(map[int][]string)
+	for _, cta := range ctas {
+		if !cta.Primary {
+			continue
+		}
+		band := int(cta.Rect.Y / fold)
+		label := normalizeCTALabel(cta.Label)
+		if !containsString(bands[band], label) {
+			bands[band] = append(bands[band], label)
+		}
+	}
+
+	var keys []int
+	for band := range bands {
+		keys = append(keys, band)
+	}
+	sort.Ints(keys)
+	for _, band := range keys {
+		if len(bands[band]) > 2 {
+			groups = append(groups, types.CTAGroup{Top: float64(band) * fold, Labels: bands[band]})
+		}
+	}
+	return groups
+}
+
+// scoreCTAAudit turns the findings into a 0-100 score and a list of issues.
+func scoreCTAAudit(audit *types.CTAAudit) {
+	if audit.Total == 0 {
+		audit.Issues = append(audit.Issues, "No buttons or button-like links found")
+		return
+	}
+
+	score := 100
+	deduct := func(points, limit int, reason string) {
+		if points > limit {
+			points = limit
+		}
+		score -= points
+		audit.Issues = append(audit.Issues, fmt.Sprintf("%s (-%d)", reason, points))
+	}
+
+	if !audit.PrimaryAbove {
+		deduct(30, 30, "No primary call to action is visible above the fold")
+	}
+
+	vaguePrimary, vagueOther := 0, 0
+	for _, cta := range audit.CTAs {
+		if cta.VagueLabel && cta.Primary {
+			vaguePrimary++
+		} else if cta.VagueLabel {
+			vagueOther++
+		}
+		if !cta.Probed {
+			continue
+		}
+		if len(cta.HoverChange) == 0 {
+			audit.NoHoverState++
+		}
+		if len(cta.FocusChange) == 0 {
+			audit.NoFocusState++
+		}
+	}
+	if vaguePrimary > 0 {
+		deduct(10*vaguePrimary, 20, fmt.Sprintf("%d primary CTA(s) have vague labels such as \"Click here\" or \"Submit\"", vaguePrimary))
+	}
+	if vagueOther > 0 {
+		deduct(2*vagueOther, 10, fmt.Sprintf("%d secondary CTA(s) have vague labels", vagueOther))
+	}
+	if len(audit.Competing) > 0 {
+		deduct(10*len(audit.Competing), 20, fmt.Sprintf("%d screen(s) show more than two competing primary CTAs", len(audit.Competing)))
+	}
+	if audit.NoHoverState > 0 {
+		deduct(5*audit.NoHoverState, 15, fmt.Sprintf("%d prominent CTA(s) do not change on hover", audit.NoHoverState))
+	}
+	if audit.NoFocusState > 0 {
+		deduct(5*audit.NoFocusState, 15, fmt.Sprintf("%d prominent CTA(s) show no focus indicator", audit.NoFocusState))
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	audit.Score = score
+}
+
+func normalizeCTALabel(label string) string {
+	return strings.Join(strings.Fields(strings.ToLower(ctaLabelTrim.ReplaceAllString(label, " "))), " ")
+}
+
+// isVagueCTALabel reports labels that are empty or generic.
+func isVagueCTALabel(label string) bool {
+	normalized := normalizeCTALabel(label)
+	return normalized == "" || vagueCTALabels[normalized]
+}
@@ -0,0 +1,121 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"time"
+
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+	"uxlyze/analyzer/pkg/visualdiff"
+
+	"github.com/chromedp/chromedp"
+)
+
+// designSettleDelay gives web fonts and late layout shifts time to finish before the page is
+// captured for a design comparison.
+const designSettleDelay = 2 * time.Second
+
+type designPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// DesignDevice completes the device a reference design export was made for. The viewport
+// width is the CSS width of the design, and the device pixel ratio is derived from it so
+// that @2x exports line up pixel for pixel; a zero width uses the width of the reference and
+// a zero height the desktop height.
+func DesignDevice(reference image.Image, device screenshot.Device) (screenshot.Device, error) {
+	refWidth, refHeight := reference.Bounds().Dx(), reference.Bounds().Dy()
+	if refWidth == 0 || refHeight == 0 {
+		return device, fmt.Errorf("reference design is empty")
+	}
+	if device.Width <= 0 {
+		device.Width = int64(refWidth)
+	}
+	if device.Height <= 0 {
+		device.Height = screenshot.DesktopDevice.Height
+	}
+	device.Scale = float64(refWidth) / float64(device.Width)
+	return device, nil
+}
+
+// CompareWithDesign captures the current page and compares it with a reference design
+// export. The page must already be emulated with the device from DesignDevice. The capture
+// covers the full height of the reference, not just the viewport.
+func CompareWithDesign(ctx context.Context, reference image.Image, device screenshot.Device, opts visualdiff.Options) (*types.DesignComparison, error) {
+	fmt.Println("Comparing page with reference design...")
+
+	if err := chromedp.Run(ctx, chromedp.Sleep(designSettleDelay)); err != nil {
+		return nil, err
+	}
+	refHeight := reference.Bounds().Dy()
+	shot, err := screenshot.CaptureArea(ctx, 0, 0, float64(device.Width), math.Ceil(float64(refHeight)/device.Scale))
+	if err != nil {
+		return nil, err
+	}
+	capture, err := visualdiff.DecodeBase64Image(shot)
+	if err != nil {
+		return nil, fmt.Errorf("decoding page capture: %w", err)
+	}
+
+	comparison, heatmap := visualdiff.CompareDesign(reference, capture, opts)
+	comparison.ViewportWidth = device.Width
+	comparison.PixelRatio = math.Round(device.Scale*100) / 100
+	comparison.Heatmap, err = visualdiff.EncodePNG(heatmap)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := labelDesignRegions(ctx, comparison, device.Scale); err != nil {
+		return comparison, err
+	}
+	return comparison, nil
+}
+
+// labelDesignRegions looks up the element at the center of every deviating region. Region
+// coordinates are in aligned reference pixels, so the alignment offset and pixel ratio are
+// undone to get back to page coordinates.
+func labelDesignRegions(ctx context.Context, comparison *types.DesignComparison, scale float64) error {
+	if len(comparison.Regions) == 0 {
+		return nil
+	}
+	points := make([]designPoint, len(comparison.Regions))
+	for i, region := range comparison.Regions {
+		r := region.Rect
+		points[i] = designPoint{
+			X: (r.X + r.Width/2 - float64(comparison.OffsetX)) / scale,
+			Y: (r.Y + r.Height/2 - float64(comparison.OffsetY)) / scale,
+		}
+	}
+	payload, err := json.Marshal(points)
+	if err != nil {
+		return err
+	}
+
+	var selectors []string
+	err = chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const points = `+string(payload)+`;
+			const selectors = points.map(p => {
+				window.scrollTo(0, Math.max(0, p.y - window.innerHeight / 2));
+				const el = document.elementFromPoint(p.x - window.scrollX, p.y - window.scrollY);
+				return el ? cssPath(el) : '';
+			});
+			window.scrollTo(0, 0);
+			return selectors;
+		})()
+	`, &selectors))
+	if err != nil {
+		return err
+	}
+	for i := range comparison.Regions {
+		if i < len(selectors) {
+			comparison.Regions[i].Selector = selectors[i]
+		}
+	}
+	return nil
+}
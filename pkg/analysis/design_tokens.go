@@ -0,0 +1,299 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// maxTokensPerGroup keeps rarely used values out of radius and shadow groups.
+const maxTokensPerGroup = 8
+
+var weightNames = map[string]string{
+	"100": "thin", "200": "extralight", "300": "light", "400": "normal", "500": "medium",
+	"600": "semibold", "700": "bold", "800": "extrabold", "900": "black",
+}
+
+// sizeNames are the t-shirt names for radius and shadow tokens, smallest first.
+var sizeNames = []string{"sm", "md", "lg", "xl", "2xl", "3xl", "4xl", "5xl", "6xl"}
+
+var lengthPattern = regexp.MustCompile(`-?[\d.]+px`)
+
+type usageCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type rawTokenStyles struct {
+	Radii   []usageCount `json:"radii"`
+	Shadows []usageCount `json:"shadows"`
+}
+
+// AnalyzeDesignTokens derives design tokens from the palette, typography and spacing
+// audits, collects the border radii and shadows in use, and renders the result as W3C
+// design tokens JSON, CSS custom properties and a Tailwind theme snippet.
+func AnalyzeDesignTokens(ctx context.Context, palette *types.ColorPalette, typography *types.TypographyAudit, spacing *types.SpacingAudit) (*types.DesignTokens, error) {
+	fmt.Println("Analyzing design tokens...")
+	var raw rawTokenStyles
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const radii = {};
+			const shadows = {};
+			document.querySelectorAll('body *').forEach(el => {
+				if (!isVisible(el)) return;
+				const style = window.getComputedStyle(el);
+				// Use the top-left radius; elements with mixed corners are rare and usually deliberate.
+				const radius = style.borderTopLeftRadius;
+				if (radius && radius !== '0px') radii[radius] = (radii[radius] || 0) + 1;
+				if (style.boxShadow && style.boxShadow !== 'none') shadows[style.boxShadow] = (shadows[style.boxShadow] || 0) + 1;
+			});
+			const list = counts => Object.keys(counts).map(value => ({ value, count: counts[value] }));
+			return { radii: list(radii), shadows: list(shadows) };
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := buildDesignTokens(palette, typography, spacing, raw)
+	tokens.Exports = types.TokenExports{
+		W3C:      exportW3CTokens(tokens),
+		CSS:      exportCSSTokens(tokens),
+		Tailwind: exportTailwindTokens(tokens),
+	}
+	return tokens, nil
+}
+
+func buildDesignTokens(palette *types.ColorPalette, typography *types.TypographyAudit, spacing *types.SpacingAudit, raw rawTokenStyles) *types.DesignTokens {
+	tokens := &types.DesignTokens{
+		Colors:       []types.DesignToken{},
+		FontFamilies: []types.DesignToken{},
+		FontSizes:    []types.DesignToken{},
+		FontWeights:  []types.DesignToken{},
+		Spacing:      []types.DesignToken{},
+		Radii:        []types.DesignToken{},
+		Shadows:      []types.DesignToken{},
+	}
+
+	if palette != nil {
+		perRole := make(map[string]int)
+		for _, c := range palette.Colors {
+			perRole[c.Role]++
+			tokens.Colors = append(tokens.Colors, types.DesignToken{
+				Name: fmt.Sprintf("%s-%d", c.Role, perRole[c.Role]), Value: c.Hex, Type: "color", Usage: c.Elements,
+			})
+		}
+	}
+
+	if typography != nil {
+		families := sortedByUsage(typography.Families)
+		for i, family := range families {
+			name := "primary"
+			switch i {
+			case 0:
+			case 1:
+				name = "secondary"
+			default:
+				name = fmt.Sprintf("family-%d", i+1)
+			}
+			tokens.FontFamilies = append(tokens.FontFamilies, types.DesignToken{
+				Name: name, Value: strconv.Quote(family.Value), Type: "fontFamily", Usage: family.Count,
+			})
+		}
+
+		baseIndex := 0
+		for i, step := range typography.Scale {
+			if step.Size == typography.BaseSize {
+				baseIndex = i
+			}
+		}
+		for i, step := range typography.Scale {
+			tokens.FontSizes = append(tokens.FontSizes, types.DesignToken{
+				Name: relativeSizeName(i - baseIndex), Value: formatPx(step.Size), Type: "dimension", Usage: step.Elements,
+			})
+		}
+
+		var weights []usageCount
+		for weight, chars := range typography.Weights {
+			weights = append(weights, usageCount{weight, chars})
+		}
+		sort.Slice(weights, func(i, j int) bool { return atoiOrZero(weights[i].Value) < atoiOrZero(weights[j].Value) })
+		for _, w := range weights {
+			name, ok := weightNames[w.Value]
+			if !ok {
+				name = "weight-" + w.Value
+			}
+			tokens.FontWeights = append(tokens.FontWeights, types.DesignToken{Name: name, Value: w.Value, Type: "fontWeight", Usage: w.Count})
+		}
+	}
+
+	if spacing != nil {
+		for _, v := range spacing.Scale {
+			// Skip one-off values and, when the page has a grid, values off that grid.
+			if v.Count < 2 || (spacing.OnGrid && !isOnGrid(v.Value, spacing.GridUnit)) {
+				continue
+			}
+			tokens.Spacing = append(tokens.Spacing, types.DesignToken{
+				Name: strconv.FormatFloat(v.Value/4, 'f', -1, 64), Value: formatPx(v.Value), Type: "dimension", Usage: v.Count,
+			})
+		}
+	}
+
+	// Pill and circle radii such as 50% and 9999px all read as "full"; the most used one gets
+	// the name and the others are numbered so token names stay unique.
+	var sized, full []usageCount
+	for _, r := range topUsage(raw.Radii, maxTokensPerGroup) {
+		if radiusSize(r.Value) < 9999 {
+			sized = append(sized, r)
+		} else {
+			full = append(full, r)
+		}
+	}
+	sort.Slice(sized, func(i, j int) bool { return radiusSize(sized[i].Value) < radiusSize(sized[j].Value) })
+	for i, r := range sized {
+		tokens.Radii = append(tokens.Radii, types.DesignToken{Name: tShirtName(i, len(sized)), Value: r.Value, Type: "dimension", Usage: r.Count})
+	}
+	for i, r := range full {
+		name := "full"
+		if i > 0 {
+			name = fmt.Sprintf("full-%d", i+1)
+		}
+		tokens.Radii = append(tokens.Radii, types.DesignToken{Name: name, Value: r.Value, Type: "dimension", Usage: r.Count})
+	}
+
+	shadows := topUsage(raw.Shadows, maxTokensPerGroup)
+	sort.Slice(shadows, func(i, j int) bool { return shadowSize(shadows[i].Value) < shadowSize(shadows[j].Value) })
+	for i, s := range shadows {
+		tokens.Shadows = append(tokens.Shadows, types.DesignToken{
+			Name: tShirtName(i, len(shadows)), Value: s.Value, Type: "shadow", Usage: s.Count,
+		})
+	}
+
+	return tokens
+}
+
+// relativeSizeName names a font size by its rank relative to the base size:
+// base, lg, xl, 2xl... upwards and sm, xs, 2xs... downwards.
+func relativeSizeName(rank int) string {
+	switch {
+	case rank == 0:
+		return "base"
+	case rank == -1:
+		return "sm"
+	case rank == -2:
+		return "xs"
+	case rank < -2:
+		return fmt.Sprintf("%dxs", -rank-1)
+	case rank == 1:
+		return "lg"
+	case rank == 2:
+		return "xl"
+	default:
+		return fmt.Sprintf("%dxl", rank-1)
+	}
+}
+
+// tShirtName names the i-th of n values, starting at "sm" and falling back to numbers
+// when there are more values than sizes.
+func tShirtName(i, n int) string {
+	if n > len(sizeNames) || i >= len(sizeNames) {
+		return strconv.Itoa(i + 1)
+	}
+	return sizeNames[i]
+}
+
+func sortedByUsage(counts map[string]int) []usageCount {
+	var list []usageCount
+	for value, count := range counts {
+		list = append(list, usageCount{value, count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Value < list[j].Value
+	})
+	return list
+}
+
+func topUsage(list []usageCount, n int) []usageCount {
+	sorted := append([]usageCount(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// radiusSize orders radii; percentages and huge pixel values are pills or circles.
+func radiusSize(value string) float64 {
+	if strings.HasSuffix(value, "%") {
+		return math.Inf(1)
+	}
+	px, err := strconv.ParseFloat(strings.TrimSuffix(strings.Fields(value + " ")[0], "px"), 64)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return px
+}
+
+// shadowSize orders shadows by the sum of vertical offsets and blur of their layers.
+func shadowSize(value string) float64 {
+	size := 0.0
+	for _, layer := range splitTopLevel(value) {
+		lengths := lengthPattern.FindAllString(layer, -1)
+		for i, length := range lengths {
+			if i == 0 || i > 2 {
+				continue // x offset and spread hardly change how big a shadow looks
+			}
+			n, _ := strconv.ParseFloat(strings.TrimSuffix(length, "px"), 64)
+			size += math.Abs(n)
+		}
+	}
+	return size
+}
+
+// splitTopLevel splits a comma separated CSS value, ignoring commas inside parentheses.
+func splitTopLevel(value string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range value {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(value[start:]))
+}
+
+func formatPx(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64) + "px"
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"github.com/chromedp/cdproto/runtime"
+)
+
+// evalAwaitPromise makes EvaluateAsDevTools wait for scripts that return a Promise.
+func evalAwaitPromise(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+	return p.WithAwaitPromise(true)
+}
+
+// domHelpersJS holds small helpers shared by the in-page analyzer scripts.
+// It is meant to be concatenated at the top of an IIFE body.
+const domHelpersJS = `
+	// Build a reasonably unique CSS selector for an element
+	function cssPath(el) {
+		if (!(el instanceof Element)) return '';
+		const parts = [];
+		while (el && el.nodeType === Node.ELEMENT_NODE && parts.length < 6) {
+			if (el.id && document.querySelectorAll('#' + CSS.escape(el.id)).length === 1) {
+				parts.unshift('#' + CSS.escape(el.id));
+				break;
+			}
+			let part = el.tagName.toLowerCase();
+			const parent = el.parentElement;
+			if (parent) {
+				const siblings = Array.from(parent.children).filter(s => s.tagName === el.tagName);
+				if (siblings.length > 1) {
+					part += ':nth-of-type(' + (siblings.indexOf(el) + 1) + ')';
+				}
+			}
+			parts.unshift(part);
+			el = parent;
+		}
+		return parts.join(' > ');
+	}
+
+	// Page-relative bounding box of an element
+	function rectOf(el) {
+		const r = el.getBoundingClientRect();
+		return {
+			x: r.left + window.scrollX,
+			y: r.top + window.scrollY,
+			width: r.width,
+			height: r.height
+		};
+	}
+
+	// Trimmed outer HTML of an element, limited to the opening tag area
+	function snippetOf(el) {
+		const html = el.outerHTML || '';
+		return html.length > 200 ? html.substring(0, 200) + '...' : html;
+	}
+
+	// Approximate accessible name computation (aria-labelledby, aria-label, content, title)
+	function accessibleName(el) {
+		const labelledBy = el.getAttribute('aria-labelledby');
+		if (labelledBy) {
+			const text = labelledBy.split(/\s+/)
+				.map(id => document.getElementById(id))
+				.filter(Boolean)
+				.map(ref => ref.textContent.trim())
+				.join(' ')
+				.trim();
+			if (text) return text;
+		}
+		const ariaLabel = (el.getAttribute('aria-label') || '').trim();
+		if (ariaLabel) return ariaLabel;
+		if (el.tagName === 'INPUT' && ['button', 'submit', 'reset'].includes(el.type)) {
+			return (el.value || '').trim();
+		}
+		if (el.tagName === 'INPUT' && el.type === 'image') {
+			return (el.getAttribute('alt') || '').trim();
+		}
+		let text = (el.innerText || el.textContent || '').trim();
+		if (!text) {
+			text = Array.from(el.querySelectorAll('img[alt], svg title'))
+				.map(node => (node.getAttribute('alt') || node.textContent || '').trim())
+				.join(' ')
+				.trim();
+		}
+		if (text) return text;
+		return (el.getAttribute('title') || '').trim();
+	}
+
+	// Whether an element is rendered and takes up space
+	function isVisible(el) {
+		const style = window.getComputedStyle(el);
+		if (style.display === 'none' || style.visibility === 'hidden' || parseFloat(style.opacity) === 0) {
+			return false;
+		}
+		const r = el.getBoundingClientRect();
+		return r.width > 0 && r.height > 0;
+	}
+`
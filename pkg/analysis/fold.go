@@ -0,0 +1,310 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// foldScreenshotRatio is how much of the page below the fold the fold screenshot shows,
+// relative to the viewport height.
+const foldScreenshotRatio = 1.5
+
+// Thresholds above which a share of the first screen is reported as an issue.
+const (
+	maxFoldOverlayShare    = 0.3
+	maxFoldAdShare         = 0.3
+	maxFoldWhitespaceShare = 0.6
+)
+
+type rawFoldTarget struct {
+	Text    string `json:"text"`
+	Visible bool   `json:"visible"`
+}
+
+type rawFold struct {
+	PageHeight       float64             `json:"pageHeight"`
+	Headings         []types.FoldElement `json:"headings"`
+	CTAs             []types.FoldElement `json:"ctas"`
+	Images           []types.FoldElement `json:"images"`
+	TextBlocks       []types.FoldElement `json:"textBlocks"`
+	Overlays         []types.FoldElement `json:"overlays"`
+	Words            int                 `json:"words"`
+	ContentShare     float64             `json:"contentShare"`
+	AdShare          float64             `json:"adShare"`
+	OverlayShare     float64             `json:"overlayShare"`
+	ValueProposition *rawFoldTarget      `json:"valueProposition"`
+	PrimaryCTA       *rawFoldTarget      `json:"primaryCta"`
+}
+
+// foldJS measures the first viewport. The fold is rasterized into 8px cells that are marked
+// as content, ad or overlay; overlays win over ads and ads over content.
+const foldJS = `
+(function() {` + domHelpersJS + ctaFinderJS + `
+	window.scrollTo(0, 0);
+	const vw = window.innerWidth;
+	const vh = window.innerHeight;
+	const CELL = 8;
+	const MAX_ITEMS = 20;
+	const cols = Math.ceil(vw / CELL);
+	const rows = Math.ceil(vh / CELL);
+	const grid = new Uint8Array(cols * rows);
+	const CONTENT = 1, AD = 2, OVERLAY = 3;
+
+	const paint = (r, value) => {
+		const x0 = Math.max(0, Math.floor(r.left / CELL)), x1 = Math.min(cols, Math.ceil(r.right / CELL));
+		const y0 = Math.max(0, Math.floor(r.top / CELL)), y1 = Math.min(rows, Math.ceil(r.bottom / CELL));
+		for (let y = y0; y < y1; y++) {
+			for (let x = x0; x < x1; x++) {
+				if (grid[y * cols + x] < value) grid[y * cols + x] = value;
+			}
+		}
+	};
+	const inFold = r => r.width > 0 && r.height > 0 && r.bottom > 0 && r.top < vh && r.right > 0 && r.left < vw;
+	const covered = el => {
+		const r = el.getBoundingClientRect();
+		const hit = document.elementFromPoint(r.left + r.width / 2, Math.min(r.top + r.height / 2, vh - 1));
+		return !!hit && !el.contains(hit) && !hit.contains(el);
+	};
+	const shorten = text => (text || '').replace(/\s+/g, ' ').trim().substring(0, 80);
+	const describe = (el, text) => {
+		const r = el.getBoundingClientRect();
+		return { text: shorten(text), selector: cssPath(el), partial: r.bottom > vh, covered: covered(el), rect: rectOf(el) };
+	};
+	const collect = (elements, textOf) => elements
+		.filter(el => isVisible(el) && inFold(el.getBoundingClientRect()))
+		.slice(0, MAX_ITEMS)
+		.map(el => describe(el, textOf(el)));
+	const area = el => { const r = el.getBoundingClientRect(); return r.width * r.height; };
+
+	// Content: text line boxes, media and form controls
+	let words = 0;
+	const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT, {
+		acceptNode: node => node.textContent.trim() ? NodeFilter.FILTER_ACCEPT : NodeFilter.FILTER_REJECT
+	});
+	const range = document.createRange();
+	while (walker.nextNode()) {
+		const node = walker.currentNode;
+		const parent = node.parentElement;
+		if (!parent || ['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE'].includes(parent.tagName) || !isVisible(parent)) continue;
+		range.selectNodeContents(node);
+		const rects = Array.from(range.getClientRects()).filter(inFold);
+		if (rects.length === 0) continue;
+		rects.forEach(r => paint(r, CONTENT));
+		words += node.textContent.trim().split(/\s+/).length;
+	}
+	const media = Array.from(document.querySelectorAll('img, video, canvas, svg, iframe, input, select, textarea, button'))
+		.filter(el => isVisible(el) && !(el.tagName === 'svg' && el.parentElement && el.parentElement.closest('svg')));
+	media.forEach(el => { const r = el.getBoundingClientRect(); if (inFold(r)) paint(r, CONTENT); });
+	document.querySelectorAll('body *').forEach(el => {
+		const r = el.getBoundingClientRect();
+		if (!inFold(r) || r.width * r.height < 2500) return;
+		if (window.getComputedStyle(el).backgroundImage.includes('url(')) paint(r, CONTENT);
+	});
+
+	// Ads: common ad network markup plus elements named like ad slots
+	const adPattern = /(^|[\s_-])(ad|ads|advert|advertisement|sponsored|ad-slot|adslot)([\s_-]|$)/i;
+	const adMarkup = 'ins.adsbygoogle, iframe[src*="doubleclick"], iframe[src*="googlesyndication"], ' +
+		'iframe[id^="google_ads"], [id^="div-gpt-ad"], [data-ad-slot], [data-ad-unit]';
+	const ads = Array.from(document.querySelectorAll('body *')).filter(el => isVisible(el) && area(el) >= 2500 &&
+		(el.matches(adMarkup) || adPattern.test(el.id || '') || adPattern.test(el.getAttribute('class') || '')));
+	ads.forEach(el => { const r = el.getBoundingClientRect(); if (inFold(r)) paint(r, AD); });
+
+	// Overlays: fixed elements, and sticky ones currently stuck to a viewport edge, other than
+	// the site header and navigation
+	const stuck = (style, r) => {
+		const top = parseFloat(style.top);
+		const bottom = parseFloat(style.bottom);
+		return (!isNaN(top) && Math.abs(r.top - top) < 1) || (!isNaN(bottom) && Math.abs(vh - r.bottom - bottom) < 1);
+	};
+	const overlays = Array.from(document.querySelectorAll('body *')).filter(el => {
+		const style = window.getComputedStyle(el);
+		if (!['fixed', 'sticky'].includes(style.position) || !isVisible(el)) return false;
+		const r = el.getBoundingClientRect();
+		if (style.position === 'sticky' && !stuck(style, r)) return false;
+		if (!inFold(r) || r.width * r.height < vw * vh * 0.01) return false;
+		return !el.matches('header, nav, [role="banner"], [role="navigation"]') && !el.querySelector('nav, [role="navigation"]');
+	}).filter((el, i, all) => !all.some(other => other !== el && other.contains(el)));
+	overlays.forEach(el => paint(el.getBoundingClientRect(), OVERLAY));
+
+	const cells = { 1: 0, 2: 0, 3: 0 };
+	grid.forEach(value => { if (value) cells[value]++; });
+	const total = grid.length || 1;
+
+	// Value proposition: the h1, or else the largest text near the top of the page
+	let proposition = Array.from(document.querySelectorAll('h1')).find(isVisible);
+	if (!proposition) {
+		let largest = 0;
+		document.querySelectorAll('body *').forEach(el => {
+			const hasText = Array.from(el.childNodes).some(node => node.nodeType === Node.TEXT_NODE && node.textContent.trim());
+			const r = el.getBoundingClientRect();
+			if (!hasText || r.top >= vh * 1.5 || !isVisible(el)) return;
+			const size = parseFloat(window.getComputedStyle(el).fontSize) || 0;
+			if (size > largest) { largest = size; proposition = el; }
+		});
+	}
+
+	// Primary CTA: the largest filled CTA on the page, or the first one when none is filled
+	const ctas = findCTAs();
+	const filled = ctas.filter(el => {
+		const style = window.getComputedStyle(el);
+		return !isTransparentColor(style.backgroundColor) || style.backgroundImage.includes('gradient');
+	});
+	const primary = (filled.length ? filled : ctas).reduce((best, el) => !best || area(el) > area(best) ? el : best, null);
+
+	const target = (el, text) => {
+		if (!el) return null;
+		const r = el.getBoundingClientRect();
+		return { text: shorten(text), visible: r.top + r.height / 2 < vh && r.bottom > 0 && !covered(el) };
+	};
+
+	return {
+		pageHeight: document.documentElement.scrollHeight,
+		headings: collect(Array.from(document.querySelectorAll('h1, h2, h3, h4, h5, h6, [role="heading"]')), el => el.textContent),
+		ctas: collect(ctas, el => accessibleName(el) || el.value),
+		images: collect(Array.from(document.querySelectorAll('img, video, canvas, svg')).filter(el => area(el) >= 2500),
+			el => el.getAttribute('alt') || el.getAttribute('aria-label') || (el.currentSrc || el.getAttribute('src') || el.tagName.toLowerCase()).split('/').pop()),
+		textBlocks: collect(Array.from(document.querySelectorAll('p, li, blockquote, dd, figcaption'))
+			.filter(el => el.textContent.trim().length >= 20), el => el.textContent),
+		overlays: overlays.slice(0, MAX_ITEMS).map(el => describe(el, el.getAttribute('aria-label') || el.id || el.getAttribute('class') || el.tagName.toLowerCase())),
+		words,
+		contentShare: cells[CONTENT] / total,
+		adShare: cells[AD] / total,
+		overlayShare: cells[OVERLAY] / total,
+		valueProposition: target(proposition, proposition && proposition.textContent),
+		primaryCta: target(primary, primary && (accessibleName(primary) || primary.value))
+	};
+})()
+`
+
+// foldLineJS draws the fold line and shades everything below it for the fold screenshot.
+const foldLineJS = `
+(function() {
+	window.scrollTo(0, 0);
+	const fold = window.innerHeight;
+	const overlay = document.createElement('div');
+	overlay.id = 'uxlyze-fold';
+	overlay.style.cssText = 'position:absolute;left:0;top:0;width:100%;height:0;z-index:2147483647;pointer-events:none;';
+	overlay.innerHTML =
+		'<div style="position:absolute;left:0;right:0;top:' + fold + 'px;height:' + (document.documentElement.scrollHeight - fold) +
+		'px;background:rgba(17,24,39,0.35);"></div>' +
+		'<div style="position:absolute;left:0;right:0;top:' + (fold - 2) + 'px;border-top:4px dashed #dc2626;"></div>' +
+		'<span style="position:absolute;right:8px;top:' + (fold + 6) + 'px;padding:2px 8px;border-radius:4px;' +
+		'font:bold 12px/18px sans-serif;color:#fff;background:#dc2626;">Fold ' + window.innerWidth + 'x' + fold + '</span>';
+	document.documentElement.appendChild(overlay);
+	return true;
+})()
+`
+
+// AnalyzeAboveTheFold emulates each device and records which headings, CTAs, images and text
+// blocks are visible without scrolling, how much of the first screen is content, whitespace,
+// ads or overlays, and whether the value proposition and primary CTA are visible. With
+// captureScreenshots it also captures the top of the page with the fold line drawn in.
+// It resets the emulated viewport before returning.
+func AnalyzeAboveTheFold(ctx context.Context, devices []screenshot.Device, captureScreenshots bool) ([]*types.FoldAnalysis, error) {
+	fmt.Println("Analyzing above-the-fold content...")
+	defer screenshot.ResetEmulation(ctx)
+
+	results := []*types.FoldAnalysis{}
+	for _, device := range devices {
+		if err := screenshot.Emulate(ctx, device); err != nil {
+			return results, err
+		}
+
+		var raw rawFold
+		if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(foldJS, &raw)); err != nil {
+			return results, err
+		}
+		fold := buildFoldAnalysis(device, raw)
+
+		if captureScreenshots {
+			var err error
+			fold.Screenshot, err = captureFoldLine(ctx, device, raw.PageHeight)
+			if err != nil {
+				log.Printf("Error capturing fold screenshot for %s: %v\n", device.Name, err)
+			}
+		}
+		results = append(results, fold)
+	}
+	return results, nil
+}
+
+func captureFoldLine(ctx context.Context, device screenshot.Device, pageHeight float64) (string, error) {
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(foldLineJS, nil)); err != nil {
+		return "", err
+	}
+	defer chromedp.Run(ctx, chromedp.EvaluateAsDevTools(
+		`(function() { const el = document.getElementById('uxlyze-fold'); if (el) el.remove(); return true; })()`, nil))
+
+	height := math.Min(pageHeight, float64(device.Height)*foldScreenshotRatio)
+	return screenshot.CaptureArea(ctx, 0, 0, float64(device.Width), math.Max(height, float64(device.Height)))
+}
+
+func buildFoldAnalysis(device screenshot.Device, raw rawFold) *types.FoldAnalysis {
+	fold := &types.FoldAnalysis{
+		Device:          device.Name,
+		Width:           device.Width,
+		Height:          device.Height,
+		PageHeight:      raw.PageHeight,
+		Headings:        nonNilFoldElements(raw.Headings),
+		CTAs:            nonNilFoldElements(raw.CTAs),
+		Images:          nonNilFoldElements(raw.Images),
+		TextBlocks:      nonNilFoldElements(raw.TextBlocks),
+		Overlays:        nonNilFoldElements(raw.Overlays),
+		Words:           raw.Words,
+		ContentShare:    raw.ContentShare,
+		AdShare:         raw.AdShare,
+		OverlayShare:    raw.OverlayShare,
+		WhitespaceShare: math.Max(0, 1-raw.ContentShare-raw.AdShare-raw.OverlayShare),
+		Issues:          []string{},
+	}
+	if raw.PageHeight > 0 {
+		fold.VisibleShare = math.Min(1, float64(device.Height)/raw.PageHeight)
+	}
+
+	if raw.ValueProposition != nil {
+		fold.ValueProposition = raw.ValueProposition.Text
+		fold.ValuePropositionVisible = raw.ValueProposition.Visible
+	}
+	switch {
+	case raw.ValueProposition == nil:
+		fold.Issues = append(fold.Issues, "No main heading or value proposition found")
+	case !fold.ValuePropositionVisible:
+		fold.Issues = append(fold.Issues, fmt.Sprintf("The value proposition %q is below the fold or covered", fold.ValueProposition))
+	}
+
+	if raw.PrimaryCTA != nil {
+		fold.PrimaryCTA = raw.PrimaryCTA.Text
+		fold.PrimaryCTAVisible = raw.PrimaryCTA.Visible
+	}
+	switch {
+	case raw.PrimaryCTA == nil:
+		fold.Issues = append(fold.Issues, "The page has no call to action")
+	case !fold.PrimaryCTAVisible:
+		fold.Issues = append(fold.Issues, fmt.Sprintf("The primary CTA %q is below the fold or covered", fold.PrimaryCTA))
+	}
+
+	if fold.OverlayShare > maxFoldOverlayShare {
+		fold.Issues = append(fold.Issues, fmt.Sprintf("Overlays cover %.0f%% of the first screen", fold.OverlayShare*100))
+	}
+	if fold.AdShare > maxFoldAdShare {
+		fold.Issues = append(fold.Issues, fmt.Sprintf("Ads take up %.0f%% of the first screen", fold.AdShare*100))
+	}
+	if fold.WhitespaceShare > maxFoldWhitespaceShare {
+		fold.Issues = append(fold.Issues, fmt.Sprintf("%.0f%% of the first screen is empty", fold.WhitespaceShare*100))
+	}
+	return fold
+}
+
+func nonNilFoldElements(elements []types.FoldElement) []types.FoldElement {
+	if elements == nil {
+		return []types.FoldElement{}
+	}
+	return elements
+}
@@ -0,0 +1,510 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// maxFormFields is the number of visible fields above which a form counts as long.
+const maxFormFields = 7
+
+// formAttr marks analyzed forms so safe mode can submit them one by one.
+const formAttr = "data-uxlyze-form"
+
+// fieldPurpose maps field names and labels to an autocomplete token and the input type and
+// inputmode that give mobile users the right keyboard. Earlier entries win.
+type fieldPurpose struct {
+	Token     string
+	Pattern   *regexp.Regexp
+	Type      string
+	InputMode string
+}
+
+var fieldPurposes = []fieldPurpose{
+	{"email", regexp.MustCompile(`e-?mail`), "email", ""},
+	{"tel", regexp.MustCompile(`phone|\btel\b|mobile`), "tel", ""},
+	{"url", regexp.MustCompile(`website|homepage|\burl\b`), "url", ""},
+	{"one-time-code", regexp.MustCompile(`\botp\b|one.?time|verification.?code|2fa`), "", "numeric"},
+	{"cc-number", regexp.MustCompile(`card.?num|cc.?num|credit.?card`), "", "numeric"},
+	{"cc-csc", regexp.MustCompile(`\bcvc\b|\bcvv\b|\bcsc\b|security.?code`), "", "numeric"},
+	{"cc-exp", regexp.MustCompile(`expir|exp.?date|cc.?exp`), "", ""},
+	{"current-password", regexp.MustCompile(`password|passwd|\bpwd\b`), "password", ""},
+	{"username", regexp.MustCompile(`user.?name|\blogin\b`), "", ""},
+	{"given-name", regexp.MustCompile(`first.?name|given.?name|\bfname\b`), "", ""},
+	{"family-name", regexp.MustCompile(`last.?name|surname|family.?name|\blname\b`), "", ""},
+	{"organization", regexp.MustCompile(`company|organi[sz]ation`), "", ""},
+	{"postal-code", regexp.MustCompile(`\bzip\b|zip.?code|postal|post.?code`), "", ""},
+	{"address-level2", regexp.MustCompile(`\bcity\b|\btown\b`), "", ""},
+	{"country", regexp.MustCompile(`country`), "", ""},
+	{"street-address", regexp.MustCompile(`address|street`), "", ""},
+	{"bday", regexp.MustCompile(`birth|\bdob\b`), "", ""},
+	{"name", regexp.MustCompile(`full.?name|your.?name|^name\b`), "", ""},
+}
+
+// textInputTypes are the input types whose keyboard is chosen by type and inputmode.
+var textInputTypes = map[string]bool{"": true, "text": true, "search": true, "email": true, "tel": true, "url": true, "number": true, "password": true}
+
+// hintSeparators turns names such as "billing_zip" or "user[email]" into words.
+var hintSeparators = strings.NewReplacer("_", " ", "[", " ", "]", " ", ".", " ")
+
+var requiredMarker = regexp.MustCompile(`(?i)\*|\brequired\b|\bmandatory\b`)
+var optionalMarker = regexp.MustCompile(`(?i)\boptional\b`)
+
+type rawFormField struct {
+	Selector     string `json:"selector"`
+	Tag          string `json:"tag"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	ID           string `json:"id"`
+	Label        string `json:"label"`
+	LabelSource  string `json:"labelSource"`
+	Placeholder  string `json:"placeholder"`
+	InputMode    string `json:"inputMode"`
+	Autocomplete string `json:"autocomplete"`
+	Required     bool   `json:"required"`
+	DescribedBy  bool   `json:"describedBy"`
+	BrokenRefs   bool   `json:"brokenRefs"`
+}
+
+type rawForm struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Selector     string         `json:"selector"`
+	Action       string         `json:"action"`
+	Method       string         `json:"method"`
+	NoValidate   bool           `json:"noValidate"`
+	LiveRegion   bool           `json:"liveRegion"`
+	Fields       []rawFormField `json:"fields"`
+	RequiredNote bool           `json:"requiredNote"`
+}
+
+// formSetupJS tags every form and describes its visible fields. Fields outside any form are
+// grouped into a pseudo form on the body.
+const formSetupJS = `
+(function() {` + domHelpersJS + `
+	const FIELDS = 'input:not([type="hidden"]):not([type="submit"]):not([type="button"]):not([type="reset"]):not([type="image"]), select, textarea';
+	const text = el => (el ? el.textContent : '').replace(/\s+/g, ' ').trim();
+
+	const labelOf = field => {
+		const labels = Array.from(field.labels || []).map(text).filter(Boolean).join(' ');
+		if (labels) return { label: labels, source: 'label' };
+		const labelledBy = (field.getAttribute('aria-labelledby') || '').split(/\s+/)
+			.map(id => text(document.getElementById(id))).filter(Boolean).join(' ');
+		if (labelledBy) return { label: labelledBy, source: 'aria-labelledby' };
+		for (const attr of ['aria-label', 'title', 'placeholder']) {
+			const value = (field.getAttribute(attr) || '').trim();
+			if (value) return { label: value, source: attr };
+		}
+		return { label: '', source: '' };
+	};
+	const referencesExist = (field, attr) => {
+		const ids = (field.getAttribute(attr) || '').split(/\s+/).filter(Boolean);
+		return { any: ids.length > 0, broken: ids.some(id => !document.getElementById(id)) };
+	};
+	const describeField = field => {
+		const { label, source } = labelOf(field);
+		const described = referencesExist(field, 'aria-describedby');
+		const errors = referencesExist(field, 'aria-errormessage');
+		return {
+			selector: cssPath(field),
+			tag: field.tagName.toLowerCase(),
+			type: field.tagName === 'INPUT' ? (field.getAttribute('type') || '').toLowerCase() : '',
+			name: field.getAttribute('name') || '',
+			id: field.id || '',
+			label: label.substring(0, 100),
+			labelSource: source,
+			placeholder: field.getAttribute('placeholder') || '',
+			inputMode: field.getAttribute('inputmode') || '',
+			autocomplete: field.getAttribute('autocomplete') || '',
+			required: field.required || field.getAttribute('aria-required') === 'true',
+			describedBy: (described.any && !described.broken) || (errors.any && !errors.broken),
+			brokenRefs: described.broken || errors.broken
+		};
+	};
+	const describeForm = (form, fields, id) => {
+		const heading = form.querySelector('h1, h2, h3, h4, legend');
+		const submit = form.querySelector('button[type="submit"], button:not([type]), input[type="submit"]');
+		return {
+			id,
+			name: (form.getAttribute('aria-label') || text(heading) || form.id || form.getAttribute('name') ||
+				(submit ? accessibleName(submit) : '') || 'form').substring(0, 80),
+			selector: cssPath(form),
+			action: form.getAttribute('action') || '',
+			method: (form.getAttribute('method') || 'get').toLowerCase(),
+			noValidate: !!form.noValidate,
+			liveRegion: !!form.querySelector('[role="alert"], [aria-live="assertive"], [aria-live="polite"]'),
+			requiredNote: /\*|required/i.test(Array.from(form.querySelectorAll('p, small, span, div'))
+				.filter(el => !el.querySelector(FIELDS)).map(text).join(' ')),
+			fields: fields.map(describeField)
+		};
+	};
+
+	const forms = [];
+	document.querySelectorAll('form').forEach(form => {
+		const fields = Array.from(form.querySelectorAll(FIELDS)).filter(isVisible);
+		if (fields.length === 0) return;
+		const id = String(forms.length);
+		form.setAttribute('` + formAttr + `', id);
+		forms.push(describeForm(form, fields, id));
+	});
+	const orphans = Array.from(document.querySelectorAll(FIELDS)).filter(field => !field.form && isVisible(field));
+	if (orphans.length > 0) {
+		const group = describeForm(document.body, orphans, '');
+		group.name = 'Fields outside a form';
+		forms.push(group);
+	}
+	return forms;
+})()
+`
+
+// formSubmitJS submits each tagged form with its fields cleared and records the validation
+// feedback. Navigation is prevented; the caller reloads the page afterwards anyway.
+const formSubmitJS = `
+(async function() {` + domHelpersJS + `
+	const FIELDS = 'input:not([type="hidden"]):not([type="submit"]):not([type="button"]):not([type="reset"]):not([type="image"]), select, textarea';
+	const ERRORS = '[role="alert"], [aria-live], [class*="error"], [class*="invalid"], [id*="error"]';
+	const visibleErrors = scope => Array.from(scope.querySelectorAll(ERRORS))
+		.filter(el => isVisible(el) && el.textContent.trim() && !el.querySelector(FIELDS));
+	const wait = ms => new Promise(resolve => setTimeout(resolve, ms));
+	const results = {};
+
+	let submitted = false;
+	window.addEventListener('submit', event => { submitted = true; event.preventDefault(); }, true);
+
+	for (const form of document.querySelectorAll('form[` + formAttr + `]')) {
+		const scope = form.parentElement || form;
+		const before = new Set(visibleErrors(scope));
+		const fields = Array.from(form.querySelectorAll(FIELDS)).filter(isVisible);
+		fields.forEach(field => {
+			if (field.type === 'checkbox' || field.type === 'radio') field.checked = false;
+			else if (field.tagName !== 'SELECT') field.value = '';
+		});
+
+		submitted = false;
+		let invalidEvents = 0;
+		const onInvalid = () => invalidEvents++;
+		form.addEventListener('invalid', onInvalid, true);
+		if (form.requestSubmit) {
+			form.requestSubmit();
+		} else {
+			form.dispatchEvent(new Event('submit', { bubbles: true, cancelable: true }));
+		}
+		await wait(500);
+		form.removeEventListener('invalid', onInvalid, true);
+
+		const referenced = new Set();
+		fields.forEach(field => ['aria-describedby', 'aria-errormessage'].forEach(attr =>
+			(field.getAttribute(attr) || '').split(/\s+/).filter(Boolean).forEach(id => referenced.add(id))));
+		const messages = visibleErrors(scope).filter(el => !before.has(el));
+		const active = document.activeElement;
+
+		results[form.getAttribute('` + formAttr + `')] = {
+			blocked: !submitted || invalidEvents > 0,
+			invalidFields: fields.filter(field => field.validity && !field.validity.valid).length,
+			ariaInvalid: fields.filter(field => field.getAttribute('aria-invalid') === 'true').length,
+			errorMessages: messages.map(el => el.textContent.replace(/\s+/g, ' ').trim().substring(0, 120)).slice(0, 10),
+			associatedErrors: messages.filter(el => (el.id && referenced.has(el.id)) ||
+				el.closest('[role="alert"], [aria-live="assertive"], [aria-live="polite"]')).length,
+			focusMovedToError: !!active && fields.includes(active) &&
+				((active.validity && !active.validity.valid) || active.getAttribute('aria-invalid') === 'true')
+		};
+	}
+	return results;
+})()
+`
+
+const formCleanupJS = `
+(function() {
+	document.querySelectorAll('[` + formAttr + `]').forEach(el => el.removeAttribute('` + formAttr + `'));
+	return true;
+})()
+`
+
+// AnalyzeForms audits label association, placeholder-as-label misuse, input types and
+// inputmode, autocomplete, required-field indication, field count and error message markup.
+// With submitEmpty it also submits each form empty to capture its validation behavior and
+// reloads the page afterwards. Submitting runs the page's own submit handlers, so this safe
+// mode only runs against local fixtures and when ALLOW_FORM_SUBMISSION enables it on the
+// server.
+func AnalyzeForms(ctx context.Context, pageURL string, submitEmpty bool) (*types.FormAudit, error) {
+	fmt.Println("Analyzing forms...")
+	var raw []rawForm
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(formSetupJS, &raw)); err != nil {
+		return nil, err
+	}
+
+	var submissions map[string]*types.FormSubmission
+	safeMode := submitEmpty && formSubmissionAllowed() && isLocalFixture(pageURL) && len(raw) > 0
+	switch {
+	case !submitEmpty || len(raw) == 0:
+	case !formSubmissionAllowed():
+		log.Println("Skipping empty form submission: ALLOW_FORM_SUBMISSION is not enabled")
+	case !safeMode:
+		log.Printf("Skipping empty form submission: %s is not a local fixture\n", pageURL)
+	}
+	if safeMode {
+		err := chromedp.Run(ctx,
+			chromedp.EvaluateAsDevTools(formSubmitJS, &submissions, evalAwaitPromise),
+			chromedp.Navigate(pageURL),
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_ = chromedp.Run(ctx, chromedp.EvaluateAsDevTools(formCleanupJS, nil))
+	}
+
+	audit := buildFormAudit(raw, submissions)
+	audit.SafeMode = safeMode
+	return audit, nil
+}
+
+// formSubmissionAllowed reports whether the server opted in to safe mode. Requests alone
+// cannot enable it, since any caller could otherwise make the analyzer submit forms to
+// services on its own host.
+func formSubmissionAllowed() bool {
+	allowed, _ := strconv.ParseBool(os.Getenv("ALLOW_FORM_SUBMISSION"))
+	return allowed
+}
+
+// isLocalFixture reports whether the URL points at a file or a loopback host, the only
+// targets safe mode is allowed to submit forms to.
+func isLocalFixture(pageURL string) bool {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "file" {
+		return true
+	}
+	host := u.Hostname()
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func buildFormAudit(raw []rawForm, submissions map[string]*types.FormSubmission) *types.FormAudit {
+	audit := &types.FormAudit{
+		FormCount: len(raw),
+		Forms:     []types.FormInfo{},
+		Issues:    []string{},
+	}
+
+	var unlabeled, placeholderLabels, keyboards, autocompletes, unindicated, long, errorMarkup int
+	for _, rf := range raw {
+		form := types.FormInfo{
+			Name:       rf.Name,
+			Selector:   rf.Selector,
+			Action:     rf.Action,
+			Method:     rf.Method,
+			NoValidate: rf.NoValidate,
+			FieldCount: len(rf.Fields),
+			Fields:     []types.FormField{},
+			Issues:     []string{},
+		}
+
+		indicated, optionalMarked, describedBy := 0, 0, 0
+		for _, rawField := range rf.Fields {
+			field, wrongKeyboard, noAutocomplete := buildFormField(rawField)
+			if wrongKeyboard {
+				keyboards++
+			}
+			if noAutocomplete {
+				autocompletes++
+			}
+			switch {
+			case field.LabelSource == "":
+				unlabeled++
+			case field.LabelSource == "placeholder":
+				placeholderLabels++
+			}
+			if field.Required {
+				form.RequiredCount++
+				if field.Indicated {
+					indicated++
+				}
+			} else if optionalMarker.MatchString(field.Label) {
+				optionalMarked++
+			}
+			if field.DescribedBy {
+				describedBy++
+			}
+			form.Fields = append(form.Fields, field)
+		}
+
+		// Marking either the required or the optional fields is fine, as is a note such as
+		// "* required" that explains the asterisks.
+		if form.RequiredCount > 0 && indicated == 0 && optionalMarked == 0 && !rf.RequiredNote {
+			unindicated++
+			form.Issues = append(form.Issues, fmt.Sprintf("%d required field(s) are not marked as required", form.RequiredCount))
+		}
+		if form.FieldCount > maxFormFields {
+			long++
+			form.Issues = append(form.Issues, fmt.Sprintf("Long form with %d fields; consider removing optional fields or splitting it into steps", form.FieldCount))
+		}
+		if form.RequiredCount > 0 && describedBy == 0 && !rf.LiveRegion {
+			errorMarkup++
+			form.Issues = append(form.Issues, "No field uses aria-describedby and the form has no live region for error messages")
+		}
+
+		if submission, ok := submissions[rf.ID]; ok && rf.ID != "" {
+			if submission.ErrorMessages == nil {
+				submission.ErrorMessages = []string{}
+			}
+			form.Submission = submission
+			if form.RequiredCount > 0 && !submission.Blocked && len(submission.ErrorMessages) == 0 && submission.AriaInvalid == 0 {
+				errorMarkup++
+				form.Issues = append(form.Issues, "Submitting the empty form showed no validation feedback")
+			}
+			if len(submission.ErrorMessages) > submission.AssociatedErrors {
+				errorMarkup++
+				form.Issues = append(form.Issues, "Error messages are neither linked to their field with aria-describedby nor announced by a live region")
+			}
+			if len(submission.ErrorMessages) > 0 && submission.AriaInvalid == 0 {
+				errorMarkup++
+				form.Issues = append(form.Issues, "Invalid fields are not marked with aria-invalid")
+			}
+		}
+
+		audit.FieldCount += form.FieldCount
+		audit.Forms = append(audit.Forms, form)
+	}
+
+	if audit.FieldCount == 0 {
+		audit.Score = 100
+		return audit
+	}
+
+	score := 100
+	deduct := func(points, limit int, reason string) {
+		if points > limit {
+			points = limit
+		}
+		score -= points
+		audit.Issues = append(audit.Issues, fmt.Sprintf("%s (-%d)", reason, points))
+	}
+	if unlabeled > 0 {
+		deduct(5*unlabeled, 30, fmt.Sprintf("%d field(s) have no label", unlabeled))
+	}
+	if placeholderLabels > 0 {
+		deduct(3*placeholderLabels, 15, fmt.Sprintf("%d field(s) use the placeholder as their only label", placeholderLabels))
+	}
+	if keyboards > 0 {
+		deduct(2*keyboards, 10, fmt.Sprintf("%d field(s) do not bring up the right mobile keyboard", keyboards))
+	}
+	if autocompletes > 0 {
+		deduct(2*autocompletes, 10, fmt.Sprintf("%d field(s) lack an autocomplete attribute", autocompletes))
+	}
+	if unindicated > 0 {
+		deduct(5*unindicated, 10, fmt.Sprintf("%d form(s) do not indicate required fields", unindicated))
+	}
+	if long > 0 {
+		deduct(5*long, 10, fmt.Sprintf("%d form(s) have more than %d fields", long, maxFormFields))
+	}
+	if errorMarkup > 0 {
+		deduct(5*errorMarkup, 15, fmt.Sprintf("%d problem(s) with error message markup", errorMarkup))
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	audit.Score = score
+	return audit
+}
+
+// buildFormField checks a single field and reports whether it brings up the wrong mobile
+// keyboard and whether it lacks the autocomplete token its purpose calls for.
+func buildFormField(raw rawFormField) (field types.FormField, wrongKeyboard, noAutocomplete bool) {
+	field = types.FormField{
+		Selector:     raw.Selector,
+		Tag:          raw.Tag,
+		Type:         raw.Type,
+		Name:         raw.Name,
+		Label:        raw.Label,
+		LabelSource:  raw.LabelSource,
+		Placeholder:  raw.Placeholder,
+		InputMode:    raw.InputMode,
+		Autocomplete: raw.Autocomplete,
+		Required:     raw.Required,
+		Indicated:    raw.Required && requiredMarker.MatchString(raw.Label),
+		DescribedBy:  raw.DescribedBy,
+		Issues:       []string{},
+	}
+
+	switch field.LabelSource {
+	case "":
+		field.Issues = append(field.Issues, "No label")
+	case "placeholder":
+		field.Issues = append(field.Issues, "Placeholder used as label; it disappears while typing")
+	}
+	if raw.BrokenRefs {
+		field.Issues = append(field.Issues, "aria-describedby or aria-errormessage points at a missing id")
+	}
+
+	if raw.Tag == "textarea" || (raw.Tag == "input" && !textInputTypes[raw.Type]) {
+		return field, false, false
+	}
+	purpose, ok := matchFieldPurpose(raw)
+	if !ok {
+		return field, false, false
+	}
+	field.Purpose = purpose.Token
+
+	if raw.Tag == "input" {
+		if purpose.Type != "" && raw.Type != purpose.Type && !(purpose.Type == "tel" && raw.Type == "number") {
+			field.Issues = append(field.Issues, fmt.Sprintf("Use type=\"%s\"", purpose.Type))
+			wrongKeyboard = true
+		}
+		if purpose.InputMode != "" && raw.InputMode != purpose.InputMode && raw.Type != "number" && raw.Type != "tel" {
+			field.Issues = append(field.Issues, fmt.Sprintf("Add inputmode=\"%s\" for a numeric keypad", purpose.InputMode))
+			wrongKeyboard = true
+		}
+	}
+
+	autocomplete := strings.ToLower(strings.TrimSpace(raw.Autocomplete))
+	if autocomplete == "" || autocomplete == "on" || autocomplete == "off" {
+		field.Issues = append(field.Issues, fmt.Sprintf("Add autocomplete=\"%s\"", purpose.Token))
+		noAutocomplete = true
+	}
+	return field, wrongKeyboard, noAutocomplete
+}
+
+// matchFieldPurpose recognizes a field from its autocomplete token, or else from its name, id
+// and label.
+func matchFieldPurpose(raw rawFormField) (fieldPurpose, bool) {
+	tokens := strings.Fields(strings.ToLower(raw.Autocomplete))
+	if len(tokens) > 0 {
+		last := tokens[len(tokens)-1]
+		for _, purpose := range fieldPurposes {
+			if purpose.Token == last || (purpose.Token == "current-password" && last == "new-password") {
+				return purpose, true
+			}
+		}
+	}
+
+	for _, hint := range []string{raw.Name, raw.ID, raw.Label, raw.Placeholder} {
+		hint = strings.TrimSpace(hintSeparators.Replace(strings.ToLower(hint)))
+		if hint == "" {
+			continue
+		}
+		for _, purpose := range fieldPurposes {
+			if purpose.Pattern.MatchString(hint) {
+				return purpose, true
+			}
+		}
+	}
+	return fieldPurpose{}, false
+}
@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+type rawHeading struct {
+	Level        int        `json:"level"`
+	InvalidLevel bool       `json:"invalidLevel"`
+	AriaLevel    string     `json:"ariaLevel"`
+	Text         string     `json:"text"`
+	Selector     string     `json:"selector"`
+	FontSize     float64    `json:"fontSize"`
+	FontWeight   int        `json:"fontWeight"`
+	Rect         types.Rect `json:"rect"`
+}
+
+// AnalyzeHeadings builds the heading outline of the page and checks that it has a single
+// h1, no skipped levels, no empty headings, and that computed font size/weight decrease
+// with the heading level.
+func AnalyzeHeadings(ctx context.Context) (*types.HeadingOutline, error) {
+	fmt.Println("Analyzing heading outline...")
+	var raw []rawHeading
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const headings = [];
+			document.querySelectorAll('h1, h2, h3, h4, h5, h6, [role="heading"]').forEach(el => {
+				// Not isVisible: empty headings have no height but still belong in the outline.
+				// Headings inside display: none ancestors have no client rects.
+				const visibility = window.getComputedStyle(el).visibility;
+				if (visibility === 'hidden' || visibility === 'collapse' || el.getClientRects().length === 0) return;
+				let level = parseInt(el.tagName.substring(1), 10);
+				let invalidLevel = false, ariaLevel = '';
+				if (el.getAttribute('role') === 'heading') {
+					// A missing, non-numeric or out-of-range aria-level falls back to the ARIA default of 2
+					const attr = el.getAttribute('aria-level');
+					level = attr === null ? 2 : Number(attr.trim());
+					if (!Number.isInteger(level) || level < 1 || level > 6) {
+						invalidLevel = true;
+						ariaLevel = attr;
+						level = 2;
+					}
+				}
+				const style = window.getComputedStyle(el);
+				let text = (el.innerText || '').replace(/\s+/g, ' ').trim();
+				if (!text) {
+					text = Array.from(el.querySelectorAll('img[alt]')).map(img => img.alt.trim()).join(' ').trim();
+				}
+				headings.push({
+					level,
+					invalidLevel,
+					ariaLevel,
+					text: text.substring(0, 120),
+					selector: cssPath(el),
+					fontSize: parseFloat(style.fontSize) || 0,
+					fontWeight: parseInt(style.fontWeight, 10) || 400,
+					rect: rectOf(el)
+				});
+			});
+			return headings;
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildHeadingOutline(raw), nil
+}
+
+func buildHeadingOutline(raw []rawHeading) *types.HeadingOutline {
+	outline := &types.HeadingOutline{
+		Counts:          make(map[string]int),
+		Outline:         []*types.HeadingNode{},
+		LevelStyles:     make(map[string]types.LevelStyle),
+		SkippedLevels:   []types.HeadingIssue{},
+		EmptyHeadings:   []types.HeadingIssue{},
+		InvalidLevels:   []types.HeadingIssue{},
+		HierarchyIssues: []types.HeadingIssue{},
+	}
+
+	var stack []*types.HeadingNode
+	previousLevel := 0
+	sizes := make(map[int][]float64)
+	weights := make(map[int][]float64)
+
+	for _, h := range raw {
+		outline.Counts[fmt.Sprintf("h%d", h.Level)]++
+		sizes[h.Level] = append(sizes[h.Level], h.FontSize)
+		weights[h.Level] = append(weights[h.Level], float64(h.FontWeight))
+
+		if h.Text == "" {
+			outline.EmptyHeadings = append(outline.EmptyHeadings, types.HeadingIssue{
+				Level: h.Level, Selector: h.Selector, Message: "Heading has no text", Rect: h.Rect,
+			})
+		}
+
+		if h.InvalidLevel {
+			outline.InvalidLevels = append(outline.InvalidLevels, types.HeadingIssue{
+				Level: h.Level, Text: h.Text, Selector: h.Selector, Rect: h.Rect,
+				Message: fmt.Sprintf("Invalid aria-level %q, treated as level 2", h.AriaLevel),
+			})
+		}
+
+		if h.Level > previousLevel+1 {
+			message := fmt.Sprintf("Jumps from h%d to h%d", previousLevel, h.Level)
+			if previousLevel == 0 {
+				message = fmt.Sprintf("Outline starts at h%d instead of h1", h.Level)
+			}
+			outline.SkippedLevels = append(outline.SkippedLevels, types.HeadingIssue{
+				Level: h.Level, Text: h.Text, Selector: h.Selector, Message: message, Rect: h.Rect,
+			})
+		}
+		previousLevel = h.Level
+
+		node := &types.HeadingNode{
+			Level:      h.Level,
+			Text:       h.Text,
+			Selector:   h.Selector,
+			FontSize:   h.FontSize,
+			FontWeight: h.FontWeight,
+			Rect:       h.Rect,
+			Children:   []*types.HeadingNode{},
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			outline.Outline = append(outline.Outline, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, node)
+	}
+	outline.H1Count = outline.Counts["h1"]
+
+	var levels []int
+	for level := range sizes {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+	for _, level := range levels {
+		outline.LevelStyles[fmt.Sprintf("h%d", level)] = types.LevelStyle{
+			Count:      len(sizes[level]),
+			FontSize:   median(sizes[level]),
+			FontWeight: int(median(weights[level])),
+		}
+	}
+
+	for i := 1; i < len(levels); i++ {
+		upper := outline.LevelStyles[fmt.Sprintf("h%d", levels[i-1])]
+		lower := outline.LevelStyles[fmt.Sprintf("h%d", levels[i])]
+		var message string
+		switch {
+		case lower.FontSize > upper.FontSize:
+			message = fmt.Sprintf("h%d (%.0fpx) is larger than h%d (%.0fpx)", levels[i], lower.FontSize, levels[i-1], upper.FontSize)
+		case lower.FontSize == upper.FontSize && lower.FontWeight >= upper.FontWeight:
+			message = fmt.Sprintf("h%d looks the same as h%d (%.0fpx, weight %d)", levels[i], levels[i-1], lower.FontSize, lower.FontWeight)
+		}
+		if message != "" {
+			outline.HierarchyIssues = append(outline.HierarchyIssues, types.HeadingIssue{Level: levels[i], Message: message})
+		}
+	}
+
+	return outline
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,259 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// genericAltTexts are alt values that technically exist but say nothing about the image.
+var genericAltTexts = map[string]bool{
+	"image": true, "img": true, "photo": true, "picture": true, "pic": true,
+	"graphic": true, "icon": true, "logo": true, "banner": true, "thumbnail": true,
+	"placeholder": true, "untitled": true, "alt": true, "spacer": true,
+}
+
+// legacyFormats are raster formats that usually have a smaller WebP/AVIF equivalent.
+var legacyFormats = map[string]bool{
+	"jpg": true, "jpeg": true, "png": true, "gif": true, "bmp": true,
+}
+
+// oversizeFactor is how much larger than its rendered size (at device pixel ratio)
+// an image may be before it is flagged as an oversized download.
+const oversizeFactor = 1.5
+
+type rawImage struct {
+	Kind           string     `json:"kind"`
+	Src            string     `json:"src"`
+	Alt            *string    `json:"alt"`
+	Role           string     `json:"role"`
+	AriaHidden     bool       `json:"ariaHidden"`
+	SourceTypes    []string   `json:"sourceTypes"`
+	RenderedWidth  float64    `json:"renderedWidth"`
+	RenderedHeight float64    `json:"renderedHeight"`
+	NaturalWidth   int        `json:"naturalWidth"`
+	NaturalHeight  int        `json:"naturalHeight"`
+	HasDimensions  bool       `json:"hasDimensions"`
+	Loading        string     `json:"loading"`
+	BelowFold      bool       `json:"belowFold"`
+	Selector       string     `json:"selector"`
+	Rect           types.Rect `json:"rect"`
+}
+
+type rawImageResult struct {
+	DevicePixelRatio float64    `json:"devicePixelRatio"`
+	Images           []rawImage `json:"images"`
+}
+
+// AnalyzeImages inventories <img>, <picture> and CSS background images and flags
+// accessibility, sizing, format and loading problems.
+func AnalyzeImages(ctx context.Context) (*types.ImageAudit, error) {
+	fmt.Println("Analyzing images...")
+	var raw rawImageResult
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const foldLine = window.innerHeight;
+			const images = [];
+
+			document.querySelectorAll('img').forEach(img => {
+				const picture = img.parentElement && img.parentElement.tagName === 'PICTURE' ? img.parentElement : null;
+				const sourceTypes = [];
+				if (picture) {
+					picture.querySelectorAll('source').forEach(source => {
+						const type = source.getAttribute('type') || '';
+						if (type) sourceTypes.push(type);
+					});
+				}
+				const rect = rectOf(img);
+				images.push({
+					kind: picture ? 'picture' : 'img',
+					src: img.currentSrc || img.src || '',
+					alt: img.hasAttribute('alt') ? img.getAttribute('alt') : null,
+					role: img.getAttribute('role') || '',
+					ariaHidden: img.getAttribute('aria-hidden') === 'true',
+					sourceTypes,
+					renderedWidth: rect.width,
+					renderedHeight: rect.height,
+					naturalWidth: img.naturalWidth || 0,
+					naturalHeight: img.naturalHeight || 0,
+					hasDimensions: img.hasAttribute('width') && img.hasAttribute('height'),
+					loading: img.getAttribute('loading') || '',
+					belowFold: rect.y >= foldLine,
+					selector: cssPath(img),
+					rect
+				});
+			});
+
+			document.querySelectorAll('body *').forEach(el => {
+				const bg = window.getComputedStyle(el).backgroundImage;
+				if (!bg || bg === 'none') return;
+				const match = bg.match(/url\(["']?([^"')]+)["']?\)/);
+				if (!match) return;
+				const rect = rectOf(el);
+				images.push({
+					kind: 'background',
+					src: match[1],
+					alt: el.getAttribute('aria-label'),
+					role: el.getAttribute('role') || '',
+					ariaHidden: el.getAttribute('aria-hidden') === 'true',
+					sourceTypes: [],
+					renderedWidth: rect.width,
+					renderedHeight: rect.height,
+					naturalWidth: 0,
+					naturalHeight: 0,
+					hasDimensions: true,
+					loading: '',
+					belowFold: rect.y >= foldLine,
+					selector: cssPath(el),
+					rect
+				});
+			});
+
+			return { devicePixelRatio: window.devicePixelRatio || 1, images };
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildImageAudit(raw), nil
+}
+
+func buildImageAudit(raw rawImageResult) *types.ImageAudit {
+	dpr := raw.DevicePixelRatio
+	if dpr <= 0 {
+		dpr = 1
+	}
+
+	audit := &types.ImageAudit{Images: make([]types.ImageInfo, 0, len(raw.Images))}
+	for _, img := range raw.Images {
+		info := types.ImageInfo{
+			Kind:           img.Kind,
+			Src:            img.Src,
+			HasAlt:         img.Alt != nil,
+			Format:         imageFormat(img.Src),
+			ModernSources:  modernSourceFormats(img.SourceTypes),
+			RenderedWidth:  img.RenderedWidth,
+			RenderedHeight: img.RenderedHeight,
+			NaturalWidth:   img.NaturalWidth,
+			NaturalHeight:  img.NaturalHeight,
+			HasDimensions:  img.HasDimensions,
+			Loading:        img.Loading,
+			BelowFold:      img.BelowFold,
+			Selector:       img.Selector,
+			Rect:           img.Rect,
+		}
+		if img.Alt != nil {
+			info.Alt = *img.Alt
+		}
+		info.Decorative = img.AriaHidden || img.Role == "presentation" || img.Role == "none" ||
+			(img.Kind != "background" && info.HasAlt && info.Alt == "")
+
+		if img.Kind == "background" {
+			audit.BackgroundImages++
+		} else {
+			audit.TotalImages++
+
+			if !info.HasAlt && !info.Decorative {
+				info.Issues = append(info.Issues, "Missing alt attribute")
+				audit.MissingAlt++
+			} else if isGenericAlt(info.Alt, img.Src) {
+				info.Issues = append(info.Issues, fmt.Sprintf("Generic alt text %q", info.Alt))
+				audit.GenericAlt++
+			}
+
+			if !info.HasDimensions {
+				info.Issues = append(info.Issues, "Missing width/height attributes (layout shift risk)")
+				audit.MissingDimensions++
+			}
+
+			if info.BelowFold && info.Loading != "lazy" {
+				info.Issues = append(info.Issues, "Below the fold but not lazy-loaded")
+				audit.NotLazyLoaded++
+			}
+
+			neededWidth := img.RenderedWidth * dpr
+			neededHeight := img.RenderedHeight * dpr
+			if neededWidth > 0 && neededHeight > 0 &&
+				float64(img.NaturalWidth) > neededWidth*oversizeFactor &&
+				float64(img.NaturalHeight) > neededHeight*oversizeFactor {
+				info.Issues = append(info.Issues, fmt.Sprintf("Oversized: %dx%d downloaded, %.0fx%.0f rendered",
+					img.NaturalWidth, img.NaturalHeight, img.RenderedWidth, img.RenderedHeight))
+				audit.Oversized++
+				audit.WastedPixels += int64(float64(img.NaturalWidth*img.NaturalHeight) - neededWidth*neededHeight)
+			}
+		}
+
+		if legacyFormats[info.Format] && len(info.ModernSources) == 0 {
+			info.Issues = append(info.Issues, fmt.Sprintf("Legacy %s format; consider WebP or AVIF", strings.ToUpper(info.Format)))
+			audit.LegacyFormat++
+		}
+
+		audit.Images = append(audit.Images, info)
+	}
+
+	return audit
+}
+
+// imageFormat guesses the image format from the URL path or data URI.
+func imageFormat(src string) string {
+	if strings.HasPrefix(src, "data:image/") {
+		mime := strings.TrimPrefix(src, "data:image/")
+		if i := strings.IndexAny(mime, ";,"); i >= 0 {
+			mime = mime[:i]
+		}
+		return strings.TrimSuffix(mime, "+xml")
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(u.Path), "."))
+	if ext == "jpeg" {
+		return "jpg"
+	}
+	return ext
+}
+
+func modernSourceFormats(sourceTypes []string) []string {
+	var formats []string
+	for _, t := range sourceTypes {
+		switch strings.ToLower(t) {
+		case "image/webp":
+			formats = append(formats, "webp")
+		case "image/avif":
+			formats = append(formats, "avif")
+		case "image/jxl":
+			formats = append(formats, "jxl")
+		}
+	}
+	return formats
+}
+
+// isGenericAlt reports whether alt text is a placeholder word or just the file name.
+func isGenericAlt(alt, src string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(alt))
+	if normalized == "" {
+		return false
+	}
+	if genericAltTexts[normalized] {
+		return true
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+	file := strings.ToLower(path.Base(u.Path))
+	return file != "" && (normalized == file || normalized == strings.TrimSuffix(file, path.Ext(file)))
+}
@@ -0,0 +1,324 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+)
+
+// maxFocusSteps caps the number of Tab presses so pages with hundreds of links stay fast.
+const maxFocusSteps = 400
+
+// skipLinkWindow is how many of the first focus stops are checked for a skip link.
+const skipLinkWindow = 3
+
+// skipLinkTargets are the fragments skip links conventionally point to.
+var skipLinkTargets = map[string]bool{
+	"main": true, "content": true, "main-content": true, "maincontent": true,
+	"primary": true, "skip": true, "skip-content": true, "skip-to-content": true,
+	"skip-main": true, "skip-to-main": true,
+}
+
+type rawFocusCandidate struct {
+	ID               string     `json:"id"`
+	Selector         string     `json:"selector"`
+	Tag              string     `json:"tag"`
+	Name             string     `json:"name"`
+	TabIndex         int        `json:"tabIndex"`
+	ExplicitNegative bool       `json:"explicitNegative"`
+	Rect             types.Rect `json:"rect"`
+}
+
+type rawFocusProbe struct {
+	ID           string     `json:"id"`
+	Selector     string     `json:"selector"`
+	Tag          string     `json:"tag"`
+	Name         string     `json:"name"`
+	TabIndex     int        `json:"tabIndex"`
+	Href         string     `json:"href"`
+	TargetExists bool       `json:"targetExists"`
+	TargetIsMain bool       `json:"targetIsMain"`
+	Changes      []string   `json:"changes"`
+	Rect         types.Rect `json:"rect"`
+}
+
+// focusSetupJS tags every interactive element and remembers its unfocused style so that
+// the focus indicator can be detected by diffing computed styles later on.
+const focusSetupJS = `
+(function() {` + domHelpersJS + `
+	const focusProps = ['outlineStyle', 'outlineWidth', 'outlineColor', 'boxShadow', 'borderTopColor',
+		'borderBottomColor', 'borderBottomWidth', 'backgroundColor', 'color', 'textDecorationLine'];
+	window.__uxlyzeFocusProps = focusProps;
+	if (document.activeElement && document.activeElement.blur) document.activeElement.blur();
+	window.scrollTo(0, 0);
+
+	const candidates = document.querySelectorAll('a[href], area[href], button, input, select, textarea, ' +
+		'iframe, summary, [tabindex], [contenteditable="true"], [role="button"], [role="link"], ' +
+		'[role="checkbox"], [role="tab"], [role="menuitem"], [role="switch"], [onclick]');
+	const result = [];
+	candidates.forEach((el, i) => {
+		if (!isVisible(el) || el.disabled || el.type === 'hidden') return;
+		const id = String(i);
+		el.setAttribute('data-uxlyze-focus', id);
+		const style = window.getComputedStyle(el);
+		const base = {};
+		focusProps.forEach(prop => base[prop] = style[prop]);
+		el.__uxlyzeBaseStyle = base;
+		result.push({
+			id,
+			selector: cssPath(el),
+			tag: el.tagName.toLowerCase(),
+			name: accessibleName(el).substring(0, 80),
+			tabIndex: el.tabIndex,
+			explicitNegative: el.hasAttribute('tabindex') && el.tabIndex < 0,
+			rect: rectOf(el)
+		});
+	});
+	return result;
+})()
+`
+
+// focusProbeJS describes document.activeElement and which styles changed when it got focus.
+const focusProbeJS = `
+(function() {` + domHelpersJS + `
+	const el = document.activeElement;
+	if (!el || el === document.body || el === document.documentElement) return { id: '' };
+
+	let id = el.getAttribute('data-uxlyze-focus');
+	if (id === null) {
+		window.__uxlyzeExtraFocus = (window.__uxlyzeExtraFocus || 0) + 1;
+		id = 'extra-' + window.__uxlyzeExtraFocus;
+		el.setAttribute('data-uxlyze-focus', id);
+	}
+
+	const style = window.getComputedStyle(el);
+	const changes = [];
+	const base = el.__uxlyzeBaseStyle;
+	if (base) {
+		(window.__uxlyzeFocusProps || []).forEach(prop => {
+			if (style[prop] !== base[prop]) changes.push(prop);
+		});
+	} else {
+		if (style.outlineStyle !== 'none' && parseFloat(style.outlineWidth) > 0) changes.push('outlineStyle');
+		if (style.boxShadow && style.boxShadow !== 'none') changes.push('boxShadow');
+	}
+
+	const href = el.getAttribute('href') || '';
+	let targetExists = false, targetIsMain = false;
+	if (href.startsWith('#') && href.length > 1) {
+		const fragment = decodeURIComponent(href.substring(1));
+		const target = document.getElementById(fragment) || document.getElementsByName(fragment)[0];
+		targetExists = !!target;
+		targetIsMain = !!target && (target.matches('main, [role="main"]') ||
+			!!target.querySelector('main, [role="main"]'));
+	}
+
+	return {
+		id,
+		selector: cssPath(el),
+		tag: el.tagName.toLowerCase(),
+		name: accessibleName(el).substring(0, 80),
+		tabIndex: el.tabIndex,
+		href,
+		targetExists,
+		targetIsMain,
+		changes,
+		rect: rectOf(el)
+	};
+})()
+`
+
+const focusCleanupJS = `
+(function() {
+	document.querySelectorAll('[data-uxlyze-focus]').forEach(el => {
+		el.removeAttribute('data-uxlyze-focus');
+		delete el.__uxlyzeBaseStyle;
+	});
+	if (document.activeElement && document.activeElement.blur) document.activeElement.blur();
+	window.scrollTo(0, 0);
+	return true;
+})()
+`
+
+// AnalyzeKeyboardNavigation drives the page with real Tab/Shift+Tab key events and records
+// the focus order, unreachable controls, focus traps, focus visibility and skip links.
+func AnalyzeKeyboardNavigation(ctx context.Context) (*types.KeyboardAudit, error) {
+	fmt.Println("Analyzing keyboard navigation...")
+	var candidates []rawFocusCandidate
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(focusSetupJS, &candidates)); err != nil {
+		return nil, err
+	}
+	defer chromedp.Run(ctx, chromedp.EvaluateAsDevTools(focusCleanupJS, nil))
+
+	audit := &types.KeyboardAudit{
+		FocusOrder:  []types.FocusStop{},
+		Unreachable: []types.FocusStop{},
+	}
+
+	steps := len(candidates)*2 + 10
+	if steps > maxFocusSteps {
+		steps = maxFocusSteps
+	}
+
+	seen := make(map[string]int)
+	var probes []rawFocusProbe
+	// The walk is truncated unless focus leaves the document or returns to a visited stop
+	audit.Truncated = true
+	for i := 0; i < steps; i++ {
+		probe, err := pressAndProbe(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		if probe.ID == "" {
+			// Focus left the document: either the cycle is complete or nothing is focusable yet.
+			if len(probes) > 0 || i > 2 {
+				audit.Truncated = false
+				break
+			}
+			continue
+		}
+		if pos, ok := seen[probe.ID]; ok {
+			if pos != 0 {
+				audit.FocusTrap = true
+				for _, trapped := range probes[pos:] {
+					audit.TrappedIn = append(audit.TrappedIn, trapped.Selector)
+				}
+			}
+			audit.Truncated = false
+			break
+		}
+		seen[probe.ID] = len(probes)
+		probes = append(probes, probe)
+	}
+
+	for i, probe := range probes {
+		stop := types.FocusStop{
+			Index:        i + 1,
+			Selector:     probe.Selector,
+			Tag:          probe.Tag,
+			Name:         probe.Name,
+			TabIndex:     probe.TabIndex,
+			VisibleFocus: len(probe.Changes) > 0,
+			FocusChanges: probe.Changes,
+			Rect:         probe.Rect,
+		}
+		if !stop.VisibleFocus {
+			audit.MissingFocusStyle++
+		}
+		audit.FocusOrder = append(audit.FocusOrder, stop)
+
+		if i < skipLinkWindow && !audit.HasSkipLink && probe.Tag == "a" && isSkipLink(probe) {
+			audit.HasSkipLink = true
+			audit.SkipLinkTarget = probe.Href
+			audit.SkipLinkValid = probe.TargetExists
+		}
+	}
+
+	// Candidates past a truncated walk were never given the chance to receive focus
+	for _, candidate := range candidates {
+		if audit.Truncated {
+			break
+		}
+		if _, ok := seen[candidate.ID]; ok || candidate.ExplicitNegative {
+			continue
+		}
+		audit.Unreachable = append(audit.Unreachable, types.FocusStop{
+			Selector: candidate.Selector,
+			Tag:      candidate.Tag,
+			Name:     candidate.Name,
+			TabIndex: candidate.TabIndex,
+			Rect:     candidate.Rect,
+		})
+	}
+
+	if !audit.FocusTrap && len(probes) > 1 {
+		issues, err := checkReverseOrder(ctx, probes)
+		if err != nil {
+			return nil, err
+		}
+		audit.ReverseOrderIssues = issues
+	}
+
+	return audit, nil
+}
+
+// pressAndProbe sends a Tab (or Shift+Tab) key event and reports the newly focused element.
+func pressAndProbe(ctx context.Context, shift bool) (rawFocusProbe, error) {
+	var probe rawFocusProbe
+	var opts []chromedp.KeyOption
+	if shift {
+		opts = append(opts, chromedp.KeyModifiers(input.ModifierShift))
+	}
+	err := chromedp.Run(ctx,
+		chromedp.KeyEvent(kb.Tab, opts...),
+		chromedp.EvaluateAsDevTools(focusProbeJS, &probe),
+	)
+	return probe, err
+}
+
+// checkReverseOrder focuses the last stop and walks back with Shift+Tab, counting the
+// positions that do not match the reversed forward order.
+func checkReverseOrder(ctx context.Context, forward []rawFocusProbe) (int, error) {
+	last := forward[len(forward)-1]
+	id, _ := json.Marshal(last.ID)
+	err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(
+		`(function() {
+			const el = document.querySelector('[data-uxlyze-focus=' + JSON.stringify(`+string(id)+`) + ']');
+			if (el) el.focus();
+			return !!el;
+		})()`, nil))
+	if err != nil {
+		return 0, err
+	}
+
+	issues := 0
+	for i := len(forward) - 2; i >= 0; i-- {
+		probe, err := pressAndProbe(ctx, true)
+		if err != nil {
+			return 0, err
+		}
+		if probe.ID != forward[i].ID {
+			issues++
+		}
+	}
+	return issues, nil
+}
+
+// isSkipLink reports whether an in-page link is a skip link: it is labelled as one, points
+// to a conventional skip-link fragment, or its target is or contains the main landmark.
+func isSkipLink(probe rawFocusProbe) bool {
+	if !strings.HasPrefix(probe.Href, "#") || len(probe.Href) < 2 {
+		return false
+	}
+	name := strings.ToLower(probe.Name)
+	target := strings.ToLower(probe.Href[1:])
+	return strings.Contains(name, "skip") || strings.Contains(name, "jump to") ||
+		skipLinkTargets[target] || probe.TargetIsMain
+}
+
+// CaptureFocusOrder overlays numbered badges on every focus stop and screenshots the page.
+// Stops without a visible focus indicator are drawn in red.
+func CaptureFocusOrder(ctx context.Context, audit *types.KeyboardAudit) (string, error) {
+	if audit == nil || len(audit.FocusOrder) == 0 {
+		return "", nil
+	}
+
+	boxes := make([]overlayBox, 0, len(audit.FocusOrder))
+	for _, stop := range audit.FocusOrder {
+		color := overlayOK
+		if !stop.VisibleFocus {
+			color = overlayError
+		}
+		boxes = append(boxes, overlayBox{Rect: stop.Rect, Label: strconv.Itoa(stop.Index), Color: color})
+	}
+
+	return captureWithOverlay(ctx, boxes)
+}
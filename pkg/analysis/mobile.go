@@ -0,0 +1,229 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// phoneWidths are the viewport widths horizontal overflow is checked at.
+var phoneWidths = []int64{320, 360, 375, 414}
+
+const (
+	// minLegibleFontSize follows Lighthouse: text below 12px is hard to read on phones.
+	minLegibleFontSize = 12.0
+	// mobileFriendlyScore is the score a page needs to count as mobile friendly.
+	mobileFriendlyScore = 70
+)
+
+type rawMobileLayout struct {
+	ViewportContent *string `json:"viewportContent"`
+	InnerWidth      int64   `json:"innerWidth"`
+	ScrollWidth     int64   `json:"scrollWidth"`
+	LegibleChars    int     `json:"legibleChars"`
+	TotalChars      int     `json:"totalChars"`
+	WideElements    []struct {
+		Selector string     `json:"selector"`
+		Width    float64    `json:"width"`
+		Rect     types.Rect `json:"rect"`
+	} `json:"wideElements"`
+}
+
+// mobileLayoutJS is a variable so the legibility threshold comes from minLegibleFontSize.
+var mobileLayoutJS = `
+(function() {` + domHelpersJS + `
+	const meta = document.querySelector("meta[name='viewport']");
+	const viewportWidth = window.innerWidth;
+
+	let legibleChars = 0;
+	let totalChars = 0;
+	const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT);
+	while (walker.nextNode()) {
+		const node = walker.currentNode;
+		const text = node.textContent.trim();
+		const el = node.parentElement;
+		if (!text || !el || ['SCRIPT', 'STYLE', 'NOSCRIPT'].includes(el.tagName) || !isVisible(el)) continue;
+		totalChars += text.length;
+		if (parseFloat(window.getComputedStyle(el).fontSize) >= ` + strconv.FormatFloat(minLegibleFontSize, 'f', -1, 64) + `) legibleChars += text.length;
+	}
+
+	function insideScrollContainer(el) {
+		for (let node = el.parentElement; node && node !== document.body; node = node.parentElement) {
+			const overflowX = window.getComputedStyle(node).overflowX;
+			if (['auto', 'scroll', 'hidden', 'clip'].includes(overflowX) && node.getBoundingClientRect().width <= viewportWidth + 1) {
+				return true;
+			}
+		}
+		return false;
+	}
+
+	const wideElements = [];
+	document.querySelectorAll('body *').forEach(el => {
+		const rect = el.getBoundingClientRect();
+		if (rect.width <= viewportWidth + 1 || !isVisible(el)) return;
+		const parent = el.parentElement;
+		if (parent && parent !== document.body && parent.getBoundingClientRect().width > viewportWidth + 1) return;
+		if (insideScrollContainer(el)) return;
+		wideElements.push({ selector: cssPath(el), width: rect.width, rect: rectOf(el) });
+	});
+
+	return {
+		viewportContent: meta ? (meta.getAttribute('content') || '') : null,
+		innerWidth: viewportWidth,
+		scrollWidth: Math.max(document.documentElement.scrollWidth, document.body ? document.body.scrollWidth : 0),
+		legibleChars,
+		totalChars,
+		wideElements: wideElements.slice(0, 20)
+	};
+})()
+`
+
+// AnalyzeMobileFriendly checks the viewport meta tag, horizontal overflow at common phone
+// widths, legible font sizes and fixed-width elements, and combines them into a score.
+// It changes the emulated viewport; callers are expected to reset it afterwards.
+func AnalyzeMobileFriendly(ctx context.Context) (*types.MobileFriendliness, error) {
+	fmt.Println("Analyzing mobile friendliness...")
+	result := &types.MobileFriendliness{
+		MinFontSize:  minLegibleFontSize,
+		Overflow:     []types.WidthOverflow{},
+		WideElements: []types.WideElement{},
+	}
+
+	seenWide := make(map[string]bool)
+	var fontLayout *rawMobileLayout
+	for _, width := range phoneWidths {
+		device := screenshot.MobileDevice
+		device.Width = width
+		if err := screenshot.Emulate(ctx, device); err != nil {
+			return nil, err
+		}
+
+		var layout rawMobileLayout
+		if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(mobileLayoutJS, &layout)); err != nil {
+			return nil, err
+		}
+
+		result.Overflow = append(result.Overflow, types.WidthOverflow{
+			Width:       width,
+			ScrollWidth: layout.ScrollWidth,
+			Overflows:   layout.ScrollWidth > layout.InnerWidth+1,
+		})
+		for _, wide := range layout.WideElements {
+			if seenWide[wide.Selector] {
+				continue
+			}
+			seenWide[wide.Selector] = true
+			result.WideElements = append(result.WideElements, types.WideElement{
+				Selector:      wide.Selector,
+				Width:         wide.Width,
+				ViewportWidth: width,
+				Rect:          wide.Rect,
+			})
+		}
+		if width == screenshot.MobileDevice.Width || fontLayout == nil {
+			l := layout
+			fontLayout = &l
+		}
+	}
+
+	if fontLayout.ViewportContent != nil {
+		result.Viewport = parseViewport(*fontLayout.ViewportContent)
+	}
+	if fontLayout.TotalChars > 0 {
+		result.LegibleFontShare = float64(fontLayout.LegibleChars) / float64(fontLayout.TotalChars)
+	} else {
+		result.LegibleFontShare = 1
+	}
+
+	scoreMobileFriendliness(result)
+	return result, nil
+}
+
+// parseViewport splits a viewport meta content string into its directives.
+func parseViewport(content string) types.ViewportDirectives {
+	v := types.ViewportDirectives{Present: true, Content: content}
+	for _, part := range strings.FieldsFunc(content, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch key {
+		case "width":
+			v.Width = value
+		case "initial-scale":
+			v.InitialScale = value
+		case "maximum-scale":
+			v.MaximumScale = value
+		case "user-scalable":
+			v.UserScalable = value
+		}
+	}
+
+	if v.UserScalable == "no" || v.UserScalable == "0" {
+		v.ZoomDisabled = true
+	}
+	if maxScale, err := strconv.ParseFloat(v.MaximumScale, 64); err == nil && maxScale < 2 {
+		v.ZoomDisabled = true
+	}
+	return v
+}
+
+func scoreMobileFriendliness(result *types.MobileFriendliness) {
+	score := 100
+	deduct := func(points int, reason string) {
+		score -= points
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s (-%d)", reason, points))
+	}
+
+	v := result.Viewport
+	switch {
+	case !v.Present:
+		deduct(30, "No viewport meta tag; phones render the page at desktop width")
+	case v.Width == "":
+		deduct(15, "Viewport meta tag has no width directive")
+	case v.Width != "device-width":
+		deduct(15, fmt.Sprintf("Viewport width is fixed to %s instead of device-width", v.Width))
+	}
+	if v.Present {
+		if scale, err := strconv.ParseFloat(v.InitialScale, 64); err != nil || scale != 1 {
+			deduct(5, "initial-scale is not set to 1")
+		}
+		if v.UserScalable == "no" || v.UserScalable == "0" {
+			deduct(15, "Pinch-zoom is disabled with user-scalable=no")
+		} else if v.ZoomDisabled {
+			deduct(10, fmt.Sprintf("Pinch-zoom is limited by maximum-scale=%s", v.MaximumScale))
+		}
+	}
+
+	var overflowing []string
+	for _, o := range result.Overflow {
+		if o.Overflows {
+			overflowing = append(overflowing, fmt.Sprintf("%dpx", o.Width))
+		}
+	}
+	if len(overflowing) > 0 {
+		deduct(20, "Page scrolls horizontally at "+strings.Join(overflowing, ", "))
+	}
+
+	switch {
+	case result.LegibleFontShare < 0.6:
+		deduct(20, fmt.Sprintf("Only %.0f%% of text is at least %.0fpx", result.LegibleFontShare*100, result.MinFontSize))
+	case result.LegibleFontShare < 0.9:
+		deduct(10, fmt.Sprintf("Only %.0f%% of text is at least %.0fpx", result.LegibleFontShare*100, result.MinFontSize))
+	}
+
+	if len(result.WideElements) > 0 {
+		deduct(10, fmt.Sprintf("%d element(s) are wider than the phone viewport", len(result.WideElements)))
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	result.Score = score
+	result.Passed = score >= mobileFriendlyScore
+}
@@ -72,19 +72,3 @@ func AnalyzeNavigation(ctx context.Context) (map[string]interface{}, error) {
 
 	return result, nil
 }
-
-func AnalyzeMobileFriendly(ctx context.Context) (bool, error) {
-	var viewportContent string
-	err := chromedp.Run(ctx,
-		chromedp.EvaluateAsDevTools(`document.querySelector("meta[name='viewport']")?.getAttribute("content") || ""`, &viewportContent),
-	)
-
-	if err != nil {
-		return false, err
-	}
-
-	if viewportContent != "" {
-		return true, nil
-	}
-	return false, nil
-}
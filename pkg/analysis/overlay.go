@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Overlay colors shared by the annotated screenshots.
+const (
+	overlayOK    = "#4f46e5"
+	overlayError = "#dc2626"
+)
+
+// overlayBox is a labelled rectangle drawn on top of the page before a screenshot.
+type overlayBox struct {
+	Rect  types.Rect `json:"rect"`
+	Label string     `json:"label"`
+	Color string     `json:"color"`
+}
+
+// captureWithOverlay draws the boxes over the page, screenshots the body and removes the
+// overlay again.
+func captureWithOverlay(ctx context.Context, boxes []overlayBox) (string, error) {
+	payload, err := json.Marshal(boxes)
+	if err != nil {
+		return "", err
+	}
+
+	err = chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`
+		(function() {
+			const boxes = `+string(payload)+`;
+			const overlay = document.createElement('div');
+			overlay.id = 'uxlyze-overlay';
+			overlay.style.cssText = 'position:absolute;left:0;top:0;width:0;height:0;z-index:2147483647;pointer-events:none;';
+			boxes.forEach(item => {
+				const box = document.createElement('div');
+				box.style.cssText = 'position:absolute;box-sizing:border-box;border:2px solid ' + item.color +
+					';left:' + item.rect.x + 'px;top:' + item.rect.y + 'px;width:' + item.rect.width +
+					'px;height:' + item.rect.height + 'px;';
+				if (item.label) {
+					const badge = document.createElement('span');
+					badge.textContent = item.label;
+					badge.style.cssText = 'position:absolute;left:-10px;top:-10px;min-width:20px;height:20px;' +
+						'padding:0 4px;border-radius:10px;font:bold 12px/20px sans-serif;text-align:center;' +
+						'color:#fff;background:' + item.color + ';';
+					box.appendChild(badge);
+				}
+				overlay.appendChild(box);
+			});
+			document.documentElement.appendChild(overlay);
+			return true;
+		})()
+	`, nil))
+	if err != nil {
+		return "", err
+	}
+	defer chromedp.Run(ctx, chromedp.EvaluateAsDevTools(
+		`(function() { const el = document.getElementById('uxlyze-overlay'); if (el) el.remove(); return true; })()`, nil))
+
+	return screenshot.Capture(ctx, "body")
+}
@@ -0,0 +1,291 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// clusterDeltaE merges colors that are hard to tell apart into one palette entry.
+	clusterDeltaE = 5.0
+	// matchDeltaE is how close a reported color must be to a measured one to count as the same.
+	matchDeltaE = 10.0
+	// accentChroma is the minimum colorfulness for a cluster to be considered an accent.
+	accentChroma = 20.0
+	// maxPaletteColors caps the palette; the long tail is still counted in DistinctColors.
+	maxPaletteColors = 16
+	// prominentShare is the area share above which Gemini is expected to mention a color.
+	prominentShare = 0.05
+)
+
+type colorSample struct {
+	Color       string  `json:"color"`
+	Role        string  `json:"role"`
+	Area        float64 `json:"area"`
+	Interactive bool    `json:"interactive"`
+}
+
+type paletteCluster struct {
+	lab         [3]float64
+	area        float64
+	roleArea    map[string]float64
+	interactive float64
+	elements    int
+	members     map[string]float64
+}
+
+// AnalyzeColorUsage measures the text, background and border colors painted on the page,
+// clusters them in CIELAB and weights each cluster by the area it covers.
+func AnalyzeColorUsage(ctx context.Context) (*types.ColorPalette, error) {
+	fmt.Println("Analyzing color usage...")
+	var samples []colorSample
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const samples = [];
+			const transparent = value => !value || value === 'transparent' || /rgba\(.*,\s*0\)$/.test(value);
+			const interactiveSelector = 'a, button, [role="button"], [role="link"], input[type="submit"], input[type="button"]';
+			const area = r => Math.max(0, r.width) * Math.max(0, r.height);
+			const overlap = (a, b) => {
+				const w = Math.min(a.right, b.right) - Math.max(a.left, b.left);
+				const h = Math.min(a.bottom, b.bottom) - Math.max(a.top, b.top);
+				return w > 0 && h > 0 ? w * h : 0;
+			};
+
+			// Background area is what an element paints minus what opaque descendants cover,
+			// so nested cards do not count twice.
+			const backgrounds = new Map();
+			document.querySelectorAll('*').forEach(el => {
+				if (!isVisible(el)) return;
+				const style = window.getComputedStyle(el);
+				const rect = el.getBoundingClientRect();
+				const interactive = !!el.closest(interactiveSelector);
+
+				if (!transparent(style.backgroundColor)) {
+					const entry = { color: style.backgroundColor, role: 'background', area: area(rect), interactive };
+					backgrounds.set(el, entry);
+					samples.push(entry);
+					let parent = el.parentElement;
+					while (parent && !backgrounds.has(parent)) parent = parent.parentElement;
+					if (parent) {
+						const owner = backgrounds.get(parent);
+						owner.area = Math.max(0, owner.area - overlap(rect, parent.getBoundingClientRect()));
+					}
+				}
+
+				['Top', 'Right', 'Bottom', 'Left'].forEach(side => {
+					const width = parseFloat(style['border' + side + 'Width']) || 0;
+					const color = style['border' + side + 'Color'];
+					if (width <= 0 || style['border' + side + 'Style'] === 'none' || transparent(color)) return;
+					const length = side === 'Top' || side === 'Bottom' ? rect.width : rect.height;
+					samples.push({ color, role: 'border', area: width * length, interactive });
+				});
+
+				let textArea = 0;
+				el.childNodes.forEach(node => {
+					if (node.nodeType !== Node.TEXT_NODE || !node.textContent.trim()) return;
+					const range = document.createRange();
+					range.selectNodeContents(node);
+					Array.from(range.getClientRects()).forEach(r => {
+						// Glyphs cover roughly half of their line box.
+						textArea += area(r) * 0.5;
+					});
+				});
+				if (textArea > 0 && !transparent(style.color)) {
+					samples.push({ color: style.color, role: 'text', area: textArea, interactive });
+				}
+			});
+
+			return samples.filter(s => s.area > 0);
+		})()
+		`, &samples),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildPalette(samples), nil
+}
+
+func buildPalette(samples []colorSample) *types.ColorPalette {
+	palette := &types.ColorPalette{
+		Colors: []types.PaletteColor{},
+		ByRole: make(map[string]int),
+	}
+
+	// Merge samples of the same color first, then cluster the distinct colors by area.
+	type distinctColor struct {
+		hex         string
+		lab         [3]float64
+		area        float64
+		roleArea    map[string]float64
+		interactive float64
+		elements    int
+	}
+	byHex := make(map[string]*distinctColor)
+	var distinct []*distinctColor
+	for _, s := range samples {
+		c, ok := parseCSSColor(s.Color)
+		if !ok || c.A == 0 {
+			continue
+		}
+		// Translucent colors are assumed to sit on white, which is the common case.
+		c = c.over(white)
+		hex := c.hex()
+		d, ok := byHex[hex]
+		if !ok {
+			d = &distinctColor{hex: hex, lab: c.lab(), roleArea: make(map[string]float64)}
+			byHex[hex] = d
+			distinct = append(distinct, d)
+		}
+		d.area += s.Area
+		d.roleArea[s.Role] += s.Area
+		d.elements++
+		if s.Interactive {
+			d.interactive += s.Area
+		}
+		palette.TotalArea += s.Area
+	}
+	palette.DistinctColors = len(distinct)
+	sort.Slice(distinct, func(i, j int) bool { return distinct[i].area > distinct[j].area })
+
+	var clusters []*paletteCluster
+	for _, d := range distinct {
+		var target *paletteCluster
+		best := clusterDeltaE
+		for _, c := range clusters {
+			if dE := deltaE2000(c.lab, d.lab); dE < best {
+				best, target = dE, c
+			}
+		}
+		if target == nil {
+			target = &paletteCluster{lab: d.lab, roleArea: make(map[string]float64), members: make(map[string]float64)}
+			clusters = append(clusters, target)
+		} else {
+			total := target.area + d.area
+			for i := range target.lab {
+				target.lab[i] = (target.lab[i]*target.area + d.lab[i]*d.area) / total
+			}
+		}
+		target.area += d.area
+		target.interactive += d.interactive
+		target.elements += d.elements
+		target.members[d.hex] += d.area
+		for role, a := range d.roleArea {
+			target.roleArea[role] += a
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].area > clusters[j].area })
+	if len(clusters) > maxPaletteColors {
+		clusters = clusters[:maxPaletteColors]
+	}
+
+	for _, c := range clusters {
+		share := 0.0
+		if palette.TotalArea > 0 {
+			share = c.area / palette.TotalArea
+		}
+		color := types.PaletteColor{
+			Hex:      dominantMember(c.members),
+			Lab:      [3]float64{round2(c.lab[0]), round2(c.lab[1]), round2(c.lab[2])},
+			Role:     clusterRole(c, share),
+			Area:     math.Round(c.area),
+			Share:    round2(share),
+			Elements: c.elements,
+			Members:  []string{},
+		}
+		for hex := range c.members {
+			color.Members = append(color.Members, hex)
+		}
+		sort.Strings(color.Members)
+		palette.ByRole[color.Role]++
+		palette.Colors = append(palette.Colors, color)
+	}
+
+	return palette
+}
+
+// clusterRole picks the role that paints most of the cluster's area. Colorful clusters
+// used mostly on interactive elements, or only in small amounts, are accents instead.
+func clusterRole(c *paletteCluster, share float64) string {
+	role, best := types.ColorRoleBackground, -1.0
+	for _, r := range []string{types.ColorRoleBackground, types.ColorRoleText, types.ColorRoleBorder} {
+		if c.roleArea[r] > best {
+			role, best = r, c.roleArea[r]
+		}
+	}
+	if chroma(c.lab) >= accentChroma && (c.interactive/c.area >= 0.4 || share < prominentShare) {
+		return types.ColorRoleAccent
+	}
+	return role
+}
+
+func dominantMember(members map[string]float64) string {
+	hex, best := "", -1.0
+	for h, a := range members {
+		if a > best || (a == best && h < hex) {
+			hex, best = h, a
+		}
+	}
+	return hex
+}
+
+// ComparePaletteWithGemini checks each color in Gemini's ColorScheme against the measured
+// palette, and lists prominent measured colors Gemini did not mention.
+func ComparePaletteWithGemini(palette *types.ColorPalette, scheme types.ColorScheme) *types.PaletteComparison {
+	comparison := &types.PaletteComparison{
+		Matches:    []types.ColorMatch{},
+		Unreported: []string{},
+	}
+	if palette == nil {
+		return comparison
+	}
+
+	mentioned := make(map[string]bool)
+	matched := 0
+	for _, group := range []struct {
+		name   string
+		colors []string
+	}{
+		{"primary", scheme.PrimaryColors},
+		{"secondary", scheme.SecondaryColors},
+		{"accent", scheme.AccentColors},
+	} {
+		for _, reported := range group.colors {
+			match := types.ColorMatch{Scheme: group.name, Reported: reported, DeltaE: -1}
+			if c, ok := parseCSSColor(reported); ok {
+				lab := c.over(white).lab()
+				for _, measured := range palette.Colors {
+					dE := deltaE2000(lab, measured.Lab)
+					if match.DeltaE < 0 || dE < match.DeltaE {
+						match.Nearest, match.DeltaE = measured.Hex, round2(dE)
+					}
+				}
+				match.Matched = match.DeltaE >= 0 && match.DeltaE <= matchDeltaE
+			}
+			if match.Matched {
+				matched++
+				mentioned[match.Nearest] = true
+			}
+			comparison.Matches = append(comparison.Matches, match)
+		}
+	}
+	if len(comparison.Matches) > 0 {
+		comparison.Agreement = round2(float64(matched) / float64(len(comparison.Matches)))
+	}
+
+	for _, measured := range palette.Colors {
+		if measured.Share >= prominentShare && !mentioned[measured.Hex] {
+			comparison.Unreported = append(comparison.Unreported, measured.Hex)
+		}
+	}
+
+	return comparison
+}
@@ -3,24 +3,168 @@ package analysis
 import (
 	"context"
 	"fmt"
+	"math"
+
+	"uxlyze/analyzer/pkg/types"
 
 	"github.com/chromedp/chromedp"
 )
 
-func AnalyzeReadability(ctx context.Context) (string, error) {
-	var paragraphCount int
+type rawTextBlock struct {
+	Tag  string `json:"tag"`
+	Text string `json:"text"`
+}
+
+type rawReadableContent struct {
+	Lang   string         `json:"lang"`
+	Blocks []rawTextBlock `json:"blocks"`
+}
+
+// AnalyzeReadability extracts the visible main-content text (skipping navigation, header,
+// footer and sidebar boilerplate) and computes language-aware readability metrics.
+func AnalyzeReadability(ctx context.Context) (*types.ReadabilityMetrics, error) {
+	fmt.Println("Analyzing readability...")
+	var content rawReadableContent
 	err := chromedp.Run(ctx,
-		chromedp.EvaluateAsDevTools(`document.querySelectorAll("p").length || 0`, &paragraphCount),
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const root = document.querySelector('main, [role="main"]') || document.querySelector('article') || document.body;
+			const boilerplate = 'nav, header, footer, aside, form, [role="navigation"], [role="banner"], ' +
+				'[role="contentinfo"], [role="complementary"], [role="search"], [aria-hidden="true"]';
+			const blockSelector = 'p, li, blockquote, dd, dt, td, th, h1, h2, h3, h4, h5, h6, pre, figcaption';
+
+			const blocks = [];
+			root.querySelectorAll(blockSelector).forEach(el => {
+				const wrapper = el.closest(boilerplate);
+				if (wrapper && !wrapper.closest('main, [role="main"], article')) return;
+				if (el.querySelector(blockSelector)) return;
+				if (!isVisible(el)) return;
+				const text = (el.innerText || '').replace(/\s+/g, ' ').trim();
+				if (text) blocks.push({ tag: el.tagName.toLowerCase(), text });
+			});
+
+			return { lang: document.documentElement.getAttribute('lang') || '', blocks };
+		})()
+		`, &content),
 	)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if paragraphCount > 20 {
-		res := fmt.Sprintf("High (many paragraphs (%d))", paragraphCount)
-		return res, nil
+	return computeReadability(content), nil
+}
+
+func computeReadability(content rawReadableContent) *types.ReadabilityMetrics {
+	var allWords []string
+	for _, block := range content.Blocks {
+		allWords = append(allWords, wordPattern.FindAllString(block.Text, -1)...)
+	}
+	profile, source := resolveLanguage(content.Lang, allWords)
+
+	metrics := &types.ReadabilityMetrics{
+		Language:         profile.Code,
+		LanguageSource:   source,
+		FleschFormula:    profile.EaseFormula,
+		GradeFormula:     "Flesch-Kincaid",
+		ParagraphLengths: map[string]int{"1-40": 0, "41-80": 0, "81-150": 0, "151+": 0},
+	}
+	if profile.Code == "de" {
+		metrics.GradeFormula = "Wiener Sachtextformel"
 	}
-	res := fmt.Sprintf("Low (few paragraphs (%d))", paragraphCount)
-	return res, nil
+
+	var (
+		syllables, letters, complexWords, monosyllables, longWords int
+		passiveSentences                                           int
+		paragraphWordCounts                                        []int
+	)
+	for _, block := range content.Blocks {
+		words := wordPattern.FindAllString(block.Text, -1)
+		for _, word := range words {
+			n := countSyllables(word, profile)
+			syllables += n
+			length := letterCount(word)
+			letters += length
+			if n >= 3 {
+				complexWords++
+			}
+			if n == 1 {
+				monosyllables++
+			}
+			if length > 6 {
+				longWords++
+			}
+		}
+
+		for _, sentence := range splitSentences(block.Text) {
+			metrics.Sentences++
+			if profile.Passive(sentence) {
+				passiveSentences++
+			}
+		}
+
+		if block.Tag == "p" || block.Tag == "blockquote" {
+			paragraphWordCounts = append(paragraphWordCounts, len(words))
+		}
+	}
+
+	metrics.Words = len(allWords)
+	if metrics.Words == 0 || metrics.Sentences == 0 {
+		metrics.Level = "Not enough text"
+		return metrics
+	}
+
+	words := float64(metrics.Words)
+	wordsPerSentence := words / float64(metrics.Sentences)
+	syllablesPerWord := float64(syllables) / words
+
+	metrics.AvgSentenceLength = round2(wordsPerSentence)
+	metrics.AvgWordLength = round2(float64(letters) / words)
+	metrics.AvgSyllablesPerWord = round2(syllablesPerWord)
+	metrics.FleschReadingEase = round2(math.Max(0, math.Min(100, profile.ReadingEase(wordsPerSentence, syllablesPerWord))))
+	metrics.GunningFog = round2(0.4 * (wordsPerSentence + 100*float64(complexWords)/words))
+	metrics.PassiveVoiceRatio = round2(float64(passiveSentences) / float64(metrics.Sentences))
+	metrics.ReadingTimeMinutes = math.Ceil(words/profile.WordsPerMinute*10) / 10
+	metrics.Level = readingEaseLevel(metrics.FleschReadingEase)
+
+	if profile.Code == "de" {
+		ms := 100 * float64(complexWords) / words
+		iw := 100 * float64(longWords) / words
+		es := 100 * float64(monosyllables) / words
+		metrics.GradeLevel = round2(0.1935*ms + 0.1672*wordsPerSentence + 0.1297*iw - 0.0327*es - 0.875)
+	} else {
+		metrics.GradeLevel = round2(0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59)
+	}
+
+	// Fall back to all text blocks when the page does not use <p> for its copy.
+	if len(paragraphWordCounts) == 0 {
+		for _, block := range content.Blocks {
+			paragraphWordCounts = append(paragraphWordCounts, len(wordPattern.FindAllString(block.Text, -1)))
+		}
+	}
+	total := 0
+	for _, count := range paragraphWordCounts {
+		total += count
+		if count > metrics.LongestParagraph {
+			metrics.LongestParagraph = count
+		}
+		switch {
+		case count <= 40:
+			metrics.ParagraphLengths["1-40"]++
+		case count <= 80:
+			metrics.ParagraphLengths["41-80"]++
+		case count <= 150:
+			metrics.ParagraphLengths["81-150"]++
+		default:
+			metrics.ParagraphLengths["151+"]++
+		}
+	}
+	metrics.Paragraphs = len(paragraphWordCounts)
+	metrics.AvgParagraphLength = round2(float64(total) / float64(len(paragraphWordCounts)))
+
+	return metrics
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
 }
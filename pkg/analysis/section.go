@@ -2,73 +2,283 @@ package analysis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"image"
+	"log"
+	"math"
+	"sort"
+	"time"
 
+	"uxlyze/analyzer/pkg/screenshot"
 	"uxlyze/analyzer/pkg/types"
+	"uxlyze/analyzer/pkg/visualdiff"
 
 	"github.com/chromedp/chromedp"
 )
 
-func AnalyzeSection(ctx context.Context, selector string) (*types.SectionAnalysis, error) {
-	analysis := &types.SectionAnalysis{
-		Name:        selector,
-		FontSizes:   make(map[string]int),
-		CtaStyles:   make(map[string]string),
-		ColorScheme: make(map[string]string),
+// maxSections caps segmentation so per-section screenshots stay affordable.
+const maxSections = 10
+
+// sectionAttr marks segmented elements so they can be selected reliably afterwards.
+const sectionAttr = "data-uxlyze-section"
+
+type rawSection struct {
+	Kind     string     `json:"kind"`
+	Name     string     `json:"name"`
+	Selector string     `json:"selector"`
+	Target   string     `json:"target"`
+	Rect     types.Rect `json:"rect"`
+}
+
+// AnalyzeSections segments the page into header, hero, main landmarks, repeated content
+// blocks and footer, runs AnalyzeSection on each and optionally captures each section.
+// Section screenshots are cropped from a single full-page capture.
+func AnalyzeSections(ctx context.Context, captureScreenshots bool) ([]*types.SectionAnalysis, error) {
+	fmt.Println("Analyzing page sections...")
+	segments, err := segmentPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page image.Image
+	var scale float64
+	if captureScreenshots && len(segments) > 0 {
+		page, scale, err = capturePage(ctx)
+		if err != nil {
+			log.Printf("Error capturing page for section screenshots: %v\n", err)
+		}
 	}
 
-	// Analyze font sizes
+	sections := []*types.SectionAnalysis{}
+	for _, segment := range segments {
+		section, err := AnalyzeSection(ctx, segment.Target)
+		if err != nil {
+			log.Printf("Error analyzing section %s: %v\n", segment.Name, err)
+			continue
+		}
+		section.Name = segment.Name
+		section.Kind = segment.Kind
+		section.Selector = segment.Selector
+		section.Rect = segment.Rect
+
+		if page != nil {
+			section.Screenshot, err = cropSection(page, scale, segment.Rect)
+			if err != nil {
+				log.Printf("Error capturing section %s: %v\n", segment.Name, err)
+			}
+		}
+		sections = append(sections, section)
+	}
+
+	_ = chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`
+		document.querySelectorAll('[`+sectionAttr+`]').forEach(el => el.removeAttribute('`+sectionAttr+`'));
+	`, nil))
+
+	return sections, nil
+}
+
+// capturePage takes the full-page PNG the section screenshots are cropped from and returns
+// it with its scale from CSS to image pixels.
+func capturePage(ctx context.Context) (image.Image, float64, error) {
+	shot, err := screenshot.Take(ctx, screenshot.Options{Mode: screenshot.ModeFullPage, Format: screenshot.FormatPNG, ThumbnailWidth: -1, Delay: 2 * time.Second})
+	if err != nil || shot == nil {
+		return nil, 0, err
+	}
+	img, err := visualdiff.DecodeBase64Image(shot.Data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return img, float64(img.Bounds().Dx()) / shot.Clip.Width, nil
+}
+
+// cropSection cuts the page-relative rectangle out of the full-page capture as a base64 PNG.
+// Sections below a truncated capture are left without a screenshot.
+func cropSection(page image.Image, scale float64, rect types.Rect) (string, error) {
+	bounds := page.Bounds()
+	crop := image.Rect(
+		bounds.Min.X+int(math.Floor(rect.X*scale)), bounds.Min.Y+int(math.Floor(rect.Y*scale)),
+		bounds.Min.X+int(math.Ceil((rect.X+rect.Width)*scale)), bounds.Min.Y+int(math.Ceil((rect.Y+rect.Height)*scale)),
+	).Intersect(bounds)
+	if crop.Empty() {
+		return "", nil
+	}
+	sub, ok := page.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return "", fmt.Errorf("page capture of type %T cannot be cropped", page)
+	}
+	return visualdiff.EncodePNG(sub.SubImage(crop))
+}
+
+// segmentPage finds the sections of the page and tags each with a data attribute.
+func segmentPage(ctx context.Context) ([]rawSection, error) {
+	var segments []rawSection
 	err := chromedp.Run(ctx,
-		chromedp.EvaluateAsDevTools(`
-			const elements = document.querySelectorAll('`+selector+` *');
-			const fontSizes = {};
-			elements.forEach(el => {
-				const size = window.getComputedStyle(el).fontSize;
-				if (size) fontSizes[el.tagName] = (fontSizes[el.tagName] || 0) + 1;
+		chromedp.EvaluateAsDevTools(fmt.Sprintf(`
+		(function() {`+domHelpersJS+`
+			const MAX_SECTIONS = %d;
+			const ATTR = %q;
+			const sections = [];
+			const used = [];
+			const viewportWidth = window.innerWidth;
+			const viewportHeight = window.innerHeight;
+
+			// Repeated blocks may sit inside a main landmark; every other overlap is a duplicate.
+			const overlapsUsed = (el, kind) => used.some(other =>
+				(other.el === el || other.el.contains(el) || el.contains(other.el)) &&
+				(other.el === el || kind !== 'repeated' || other.kind !== 'main'));
+			const add = (el, kind, name) => {
+				if (!el || sections.length >= MAX_SECTIONS || !isVisible(el) || overlapsUsed(el, kind)) return;
+				used.push({ el, kind });
+				const index = sections.length;
+				el.setAttribute(ATTR, String(index));
+				sections.push({ kind, name, selector: cssPath(el), target: '[' + ATTR + '="' + index + '"]', rect: rectOf(el) });
+			};
+			const outermost = selector => Array.from(document.querySelectorAll(selector))
+				.filter(el => !el.parentElement || !el.parentElement.closest(selector));
+			const labelOf = el => {
+				const heading = el.querySelector('h1, h2, h3');
+				const label = el.getAttribute('aria-label') || (heading && heading.textContent) || el.id || el.tagName.toLowerCase();
+				return label.replace(/\s+/g, ' ').trim().substring(0, 60);
+			};
+			const areaOf = el => { const r = el.getBoundingClientRect(); return r.width * r.height; };
+
+			const header = outermost('header, [role="banner"]').find(el => !el.closest('main, article, section'));
+			const footer = outermost('footer, [role="contentinfo"]').filter(el => !el.closest('main, article, section')).pop();
+			const main = document.querySelector('main, [role="main"]');
+			const scope = main || document.body;
+
+			// The header and footer are claimed first so nothing inside them is picked as content.
+			add(header, 'header', 'Header');
+			add(footer, 'footer', 'Footer');
+
+			// Hero: the smallest large block near the top of the page that holds the h1.
+			const hero = Array.from(scope.querySelectorAll('section, div, article')).filter(el => {
+				const r = el.getBoundingClientRect();
+				return r.top + window.scrollY < viewportHeight && r.height >= viewportHeight * 0.3 &&
+					r.width >= viewportWidth * 0.6 && el.querySelector('h1') && !overlapsUsed(el, 'hero');
+			}).sort((a, b) => areaOf(a) - areaOf(b))[0];
+			add(hero, 'hero', 'Hero');
+
+			// Main landmarks: sections and regions of the main content.
+			let regions = outermost('main section, main article, main [role="region"]');
+			if (!main) regions = outermost('section, [role="region"]');
+			if (regions.length === 0 && main) regions = Array.from(main.children);
+			if (regions.length === 0) regions = Array.from(document.body.children).filter(el => el.getBoundingClientRect().height >= 100);
+			regions.filter(el => !['SCRIPT', 'STYLE', 'NOSCRIPT'].includes(el.tagName))
+				.forEach(el => add(el, 'main', 'Main: ' + labelOf(el)));
+
+			// Repeated content blocks: containers whose children mostly share tag and class.
+			scope.querySelectorAll('*').forEach(el => {
+				const children = Array.from(el.children).filter(child => isVisible(child) && child.getBoundingClientRect().width >= 100);
+				if (children.length < 3) return;
+				const signatures = {};
+				children.forEach(child => {
+					const signature = child.tagName.toLowerCase() + (child.classList[0] ? '.' + child.classList[0] : '');
+					signatures[signature] = (signatures[signature] || 0) + 1;
+				});
+				const [signature, count] = Object.entries(signatures).sort((a, b) => b[1] - a[1])[0];
+				if (count >= 3 && count >= children.length * 0.75) {
+					add(el, 'repeated', 'Repeated: ' + count + ' x ' + signature);
+				}
 			});
-			return fontSizes;
-		`, &analysis.FontSizes),
+
+			return sections;
+		})()
+		`, maxSections, sectionAttr), &segments),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Analyze button styles
-	err = chromedp.Run(ctx,
-		chromedp.EvaluateAsDevTools(`
-			const buttons = document.querySelectorAll('`+selector+` button, `+selector+` .button, `+selector+` [role="button"]');
-			const styles = {};
-			buttons.forEach(btn => {
-				const computed = window.getComputedStyle(btn);
-				styles['borderRadius'] = computed.borderRadius;
-				styles['backgroundColor'] = computed.backgroundColor;
-				styles['color'] = computed.color;
-			});
-			return styles;
-		`, &analysis.CtaStyles),
-	)
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].Rect.Y < segments[j].Rect.Y })
+	return segments, nil
+}
+
+// AnalyzeSection collects the font sizes, most prominent button style and colors used
+// inside the element matched by selector.
+func AnalyzeSection(ctx context.Context, selector string) (*types.SectionAnalysis, error) {
+	analysis := &types.SectionAnalysis{
+		Name:        selector,
+		FontSizes:   make(map[string]int),
+		CtaStyles:   make(map[string]string),
+		ColorScheme: make(map[string]int),
+	}
+
+	quoted, err := json.Marshal(selector)
 	if err != nil {
 		return nil, err
 	}
 
-	// Analyze color scheme
+	var result struct {
+		FontSizes   map[string]int    `json:"fontSizes"`
+		CtaStyles   map[string]string `json:"ctaStyles"`
+		ColorScheme map[string]int    `json:"colorScheme"`
+	}
 	err = chromedp.Run(ctx,
 		chromedp.EvaluateAsDevTools(`
-			const elements = document.querySelectorAll('`+selector+` *');
-			const colors = {};
+		(function() {
+			const root = document.querySelector(`+string(quoted)+`);
+			const result = { fontSizes: {}, ctaStyles: {}, colorScheme: {} };
+			if (!root) return result;
+
+			const elements = [root, ...root.querySelectorAll('*')];
+
+			// Analyze font sizes of elements that render text themselves
+			elements.forEach(el => {
+				const hasText = Array.from(el.childNodes).some(node => node.nodeType === Node.TEXT_NODE && node.textContent.trim());
+				if (!hasText) return;
+				const size = window.getComputedStyle(el).fontSize;
+				if (size) result.fontSizes[size] = (result.fontSizes[size] || 0) + 1;
+			});
+
+			// Analyze the style of the largest button
+			let largest = null;
+			let largestArea = 0;
+			root.querySelectorAll('button, .button, .btn, [role="button"], input[type="submit"]').forEach(btn => {
+				const r = btn.getBoundingClientRect();
+				if (r.width * r.height > largestArea) {
+					largest = btn;
+					largestArea = r.width * r.height;
+				}
+			});
+			if (largest) {
+				const computed = window.getComputedStyle(largest);
+				result.ctaStyles = {
+					borderRadius: computed.borderRadius,
+					backgroundColor: computed.backgroundColor,
+					color: computed.color
+				};
+			}
+
+			// Analyze color scheme
 			elements.forEach(el => {
-				const bg = window.getComputedStyle(el).backgroundColor;
-				const color = window.getComputedStyle(el).color;
-				if (bg && bg !== 'rgba(0, 0, 0, 0)') colors[bg] = (colors[bg] || 0) + 1;
-				if (color) colors[color] = (colors[color] || 0) + 1;
+				const style = window.getComputedStyle(el);
+				const bg = style.backgroundColor;
+				if (bg && bg !== 'rgba(0, 0, 0, 0)' && bg !== 'transparent') result.colorScheme[bg] = (result.colorScheme[bg] || 0) + 1;
+				if (el.innerText && el.innerText.trim()) result.colorScheme[style.color] = (result.colorScheme[style.color] || 0) + 1;
 			});
-			return colors;
-		`, &analysis.ColorScheme),
+
+			return result;
+		})()
+		`, &result),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	for size, count := range result.FontSizes {
+		analysis.FontSizes[size] = count
+	}
+	for property, value := range result.CtaStyles {
+		analysis.CtaStyles[property] = value
+	}
+	for color, count := range result.ColorScheme {
+		analysis.ColorScheme[color] = count
+	}
+
 	// Calculate score and generate details
 	analysis.Score = calculateScore(analysis)
 	analysis.Details = generateDetails(analysis)
@@ -85,10 +295,10 @@ func calculateScore(analysis *types.SectionAnalysis) int {
 		score += 10
 	}
 
-	if analysis.CtaStyles["borderRadius"] != "0px" {
+	if radius, ok := analysis.CtaStyles["borderRadius"]; ok && radius != "0px" {
 		score += 10
 	}
-	if analysis.CtaStyles["backgroundColor"] != "" && analysis.CtaStyles["backgroundColor"] != "transparent" {
+	if bg := analysis.CtaStyles["backgroundColor"]; bg != "" && bg != "transparent" && bg != "rgba(0, 0, 0, 0)" {
 		score += 10
 	}
 
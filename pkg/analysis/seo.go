@@ -3,35 +3,342 @@ package analysis
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
 
 	"github.com/chromedp/chromedp"
 )
 
-func AnalyzeSEO(ctx context.Context) (map[string]interface{}, error) {
-	var result map[string]interface{}
+// Recommended lengths in characters.
+const (
+	titleMinLength       = 30
+	titleMaxLength       = 60
+	descriptionMinLength = 70
+	descriptionMaxLength = 160
+)
+
+// multiValueMeta are meta properties that may legitimately appear more than once.
+var multiValueMeta = map[string]bool{
+	"og:image": true, "og:image:width": true, "og:image:height": true, "og:image:alt": true,
+	"og:image:type": true, "og:image:secure_url": true, "og:locale:alternate": true,
+	"og:video": true, "og:audio": true, "article:tag": true, "article:author": true,
+	"music:song": true, "video:actor": true, "video:tag": true, "book:tag": true,
+}
+
+var hreflangPattern = regexp.MustCompile(`^(?i)(x-default|[a-z]{2,3}(-[a-z]{4})?(-([a-z]{2}|[0-9]{3}))?)$`)
+
+type rawLink struct {
+	Lang     string `json:"lang"`
+	Raw      string `json:"raw"`
+	Resolved string `json:"resolved"`
+}
+
+type rawSEO struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Metas []struct {
+		Key     string `json:"key"`
+		Content string `json:"content"`
+	} `json:"metas"`
+	Canonicals    []rawLink `json:"canonicals"`
+	Hreflang      []rawLink `json:"hreflang"`
+	TotalImages   int       `json:"totalImages"`
+	ImagesWithAlt int       `json:"imagesWithAlt"`
+	XRobotsTag    string    `json:"xRobotsTag"`
+	HeaderChecked bool      `json:"headerChecked"`
+}
+
+// AnalyzeSEO checks the on-page SEO signals (title, description, canonical, robots,
+// hreflang, social cards, duplicate meta tags and image alt coverage) and scores them.
+func AnalyzeSEO(ctx context.Context) (*types.SEOAudit, error) {
 	fmt.Println("Analyzing SEO...")
+	var raw rawSEO
 	err := chromedp.Run(ctx,
 		chromedp.EvaluateAsDevTools(`
-		(function() {
-			const metaTags = document.querySelectorAll('meta');
-			const metaInfo = {};
-
-			metaTags.forEach(tag => {
-				const name = tag.getAttribute('name') || tag.getAttribute('property');
+		(async function() {
+			const metas = [];
+			document.querySelectorAll('meta').forEach(tag => {
+				const key = tag.getAttribute('name') || tag.getAttribute('property');
 				const content = tag.getAttribute('content');
-				if (name && content) {
-					metaInfo[name] = content;
+				if (key && content !== null) {
+					metas.push({ key: key.toLowerCase(), content });
 				}
 			});
 
-			return metaInfo;
+			const links = selector => Array.from(document.querySelectorAll(selector)).map(link => ({
+				lang: link.getAttribute('hreflang') || '',
+				raw: link.getAttribute('href') || '',
+				resolved: link.href || ''
+			}));
+
+			const images = Array.from(document.images);
+
+			// X-Robots-Tag is only visible in the response headers, so ask the server again.
+			let xRobotsTag = '';
+			let headerChecked = false;
+			try {
+				const response = await fetch(location.href, { method: 'HEAD', credentials: 'include', cache: 'no-store' });
+				xRobotsTag = response.headers.get('x-robots-tag') || '';
+				headerChecked = true;
+			} catch (e) {}
+
+			return {
+				title: document.title || '',
+				url: location.href,
+				metas,
+				canonicals: links('link[rel~="canonical"]'),
+				hreflang: links('link[rel~="alternate"][hreflang]'),
+				totalImages: images.length,
+				imagesWithAlt: images.filter(img => img.hasAttribute('alt')).length,
+				xRobotsTag,
+				headerChecked
+			};
 		})()
-		`, &result),
+		`, &raw, evalAwaitPromise),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return buildSEOAudit(raw), nil
+}
+
+func buildSEOAudit(raw rawSEO) *types.SEOAudit {
+	audit := &types.SEOAudit{
+		Title:    strings.TrimSpace(raw.Title),
+		Checks:   []types.SEOCheck{},
+		Hreflang: make(map[string]string),
+		Meta:     make(map[string]string),
+	}
+
+	metaCounts := make(map[string]int)
+	for _, m := range raw.Metas {
+		metaCounts[m.Key]++
+		if _, ok := audit.Meta[m.Key]; !ok {
+			audit.Meta[m.Key] = m.Content
+		}
+	}
+
+	add := func(id, name string, weight float64, status, message, value string) {
+		audit.Checks = append(audit.Checks, types.SEOCheck{
+			ID: id, Name: name, Status: status, Weight: weight, Message: message, Value: value,
+		})
+	}
+
+	// Title
+	titleLength := len([]rune(audit.Title))
+	switch {
+	case titleLength == 0:
+		add("title", "Title", 3, types.CheckFail, "The page has no <title>", "")
+	case titleLength < titleMinLength || titleLength > titleMaxLength:
+		add("title", "Title", 3, types.CheckWarn, fmt.Sprintf("Title is %d characters; aim for %d-%d",
+			titleLength, titleMinLength, titleMaxLength), audit.Title)
+	default:
+		add("title", "Title", 3, types.CheckPass, fmt.Sprintf("Title is %d characters", titleLength), audit.Title)
+	}
+
+	// Meta description
+	description := strings.TrimSpace(audit.Meta["description"])
+	descriptionLength := len([]rune(description))
+	switch {
+	case descriptionLength == 0:
+		add("meta-description", "Meta description", 2, types.CheckFail, "No meta description", "")
+	case descriptionLength < descriptionMinLength || descriptionLength > descriptionMaxLength:
+		add("meta-description", "Meta description", 2, types.CheckWarn, fmt.Sprintf("Description is %d characters; aim for %d-%d",
+			descriptionLength, descriptionMinLength, descriptionMaxLength), description)
+	default:
+		add("meta-description", "Meta description", 2, types.CheckPass, fmt.Sprintf("Description is %d characters", descriptionLength), description)
+	}
+
+	// Canonical
+	status, message := checkCanonical(raw)
+	if len(raw.Canonicals) > 0 {
+		audit.Canonical = raw.Canonicals[0].Resolved
+	}
+	add("canonical", "Canonical URL", 2, status, message, audit.Canonical)
+
+	// Robots
+	robots := strings.ToLower(audit.Meta["robots"] + "," + audit.Meta["googlebot"] + "," + raw.XRobotsTag)
+	directives := robotsDirectives(robots)
+	switch {
+	case directives["noindex"] || directives["none"]:
+		add("robots", "Robots directives", 3, types.CheckFail, "The page is blocked from indexing (noindex)", robots)
+	case directives["nofollow"]:
+		add("robots", "Robots directives", 3, types.CheckWarn, "Links on the page are not followed (nofollow)", robots)
+	case !raw.HeaderChecked:
+		add("robots", "Robots directives", 3, types.CheckPass, "Meta robots allows indexing (X-Robots-Tag could not be checked)", robots)
+	default:
+		add("robots", "Robots directives", 3, types.CheckPass, "Meta robots and X-Robots-Tag allow indexing", robots)
+	}
+
+	// hreflang
+	if len(raw.Hreflang) > 0 {
+		status, message := checkHreflang(raw, audit.Canonical, audit.Hreflang)
+		add("hreflang", "hreflang", 1, status, message, fmt.Sprintf("%d alternates", len(raw.Hreflang)))
+	}
+
+	// Open Graph
+	status, message = checkTagSet(audit.Meta, []string{"og:title", "og:description", "og:image", "og:url", "og:type"}, nil)
+	add("open-graph", "Open Graph", 1, status, message, "")
+
+	// Twitter card, which falls back to Open Graph for title, description and image
+	status, message = checkTagSet(audit.Meta, []string{"twitter:card", "twitter:title", "twitter:description", "twitter:image"},
+		map[string]string{"twitter:title": "og:title", "twitter:description": "og:description", "twitter:image": "og:image"})
+	add("twitter-card", "Twitter card", 1, status, message, audit.Meta["twitter:card"])
+
+	// Duplicate meta tags
+	var duplicates []string
+	for key, count := range metaCounts {
+		if count > 1 && !multiValueMeta[key] {
+			duplicates = append(duplicates, fmt.Sprintf("%s (%dx)", key, count))
+		}
+	}
+	if len(duplicates) > 0 {
+		add("duplicate-meta", "Duplicate meta tags", 1, types.CheckWarn, "Meta tags defined more than once", strings.Join(duplicates, ", "))
+	} else {
+		add("duplicate-meta", "Duplicate meta tags", 1, types.CheckPass, "No duplicate meta tags", "")
+	}
+
+	// Image alt coverage
+	if raw.TotalImages > 0 {
+		coverage := float64(raw.ImagesWithAlt) / float64(raw.TotalImages)
+		status := types.CheckPass
+		switch {
+		case coverage < 0.7:
+			status = types.CheckFail
+		case coverage < 0.95:
+			status = types.CheckWarn
+		}
+		add("image-alt", "Image alt coverage", 2, status, fmt.Sprintf("%d of %d images have an alt attribute",
+			raw.ImagesWithAlt, raw.TotalImages), fmt.Sprintf("%.0f%%", coverage*100))
+	}
+
+	audit.Score = seoScore(audit.Checks)
+	return audit
+}
+
+// robotsDirectives splits comma-separated robots directives into a set. A user agent prefix
+// as in the X-Robots-Tag "googlebot: noindex" is dropped; parameterized directives such as
+// "max-image-preview:none" are kept whole so their value is not mistaken for a directive.
+func robotsDirectives(robots string) map[string]bool {
+	directives := make(map[string]bool)
+	for _, token := range strings.Split(robots, ",") {
+		token = strings.TrimSpace(token)
+		if name, value, ok := strings.Cut(token, ":"); ok && !strings.HasPrefix(name, "max-") && name != "unavailable_after" {
+			token = strings.TrimSpace(value)
+		}
+		if token != "" {
+			directives[token] = true
+		}
+	}
+	return directives
+}
+
+func checkCanonical(raw rawSEO) (string, string) {
+	if len(raw.Canonicals) == 0 {
+		return types.CheckWarn, "No canonical URL; duplicate URLs may compete in search results"
+	}
+	if len(raw.Canonicals) > 1 {
+		return types.CheckFail, fmt.Sprintf("%d canonical links; search engines may ignore all of them", len(raw.Canonicals))
+	}
+
+	canonical, err := url.Parse(raw.Canonicals[0].Resolved)
+	if err != nil || canonical.Host == "" || (canonical.Scheme != "http" && canonical.Scheme != "https") {
+		return types.CheckFail, "Canonical URL is not a valid absolute http(s) URL"
+	}
+	if !strings.HasPrefix(raw.Canonicals[0].Raw, "http") {
+		return types.CheckWarn, "Canonical URL is relative; use an absolute URL"
+	}
+	if page, err := url.Parse(raw.URL); err == nil && !strings.EqualFold(page.Hostname(), canonical.Hostname()) {
+		return types.CheckWarn, "Canonical URL points to another host (" + canonical.Hostname() + ")"
+	}
+	return types.CheckPass, "Canonical URL is set"
+}
+
+func checkHreflang(raw rawSEO, canonical string, hreflang map[string]string) (string, string) {
+	var problems []string
+	status := types.CheckPass
+	selfReferenced := false
+	for _, alt := range raw.Hreflang {
+		if !hreflangPattern.MatchString(alt.Lang) {
+			problems = append(problems, fmt.Sprintf("invalid code %q", alt.Lang))
+			status = types.CheckFail
+			continue
+		}
+		lang := strings.ToLower(alt.Lang)
+		if existing, ok := hreflang[lang]; ok && existing != alt.Resolved {
+			problems = append(problems, fmt.Sprintf("%s points to more than one URL", lang))
+			status = types.CheckFail
+		}
+		hreflang[lang] = alt.Resolved
+		if !strings.HasPrefix(alt.Raw, "http") {
+			problems = append(problems, fmt.Sprintf("%s uses a relative URL", lang))
+		}
+		if sameURL(alt.Resolved, raw.URL) || (canonical != "" && sameURL(alt.Resolved, canonical)) {
+			selfReferenced = true
+		}
+	}
+
+	if !selfReferenced {
+		problems = append(problems, "no self-referencing alternate")
+	}
+	if _, ok := hreflang["x-default"]; !ok {
+		problems = append(problems, "no x-default")
+	}
+	if len(problems) == 0 {
+		return types.CheckPass, "hreflang annotations are consistent"
+	}
+	if status == types.CheckPass {
+		status = types.CheckWarn
+	}
+	return status, "hreflang: " + strings.Join(problems, "; ")
+}
+
+// checkTagSet passes when all tags are present, warns when some are, fails when none are.
+// fallbacks maps a tag to another tag that satisfies it.
+func checkTagSet(meta map[string]string, tags []string, fallbacks map[string]string) (string, string) {
+	var missing []string
+	for _, tag := range tags {
+		if strings.TrimSpace(meta[tag]) != "" {
+			continue
+		}
+		if fallback, ok := fallbacks[tag]; ok && strings.TrimSpace(meta[fallback]) != "" {
+			continue
+		}
+		missing = append(missing, tag)
+	}
+	switch {
+	case len(missing) == 0:
+		return types.CheckPass, "All tags present"
+	case len(missing) == len(tags):
+		return types.CheckFail, "None of " + strings.Join(tags, ", ") + " are set"
+	default:
+		return types.CheckWarn, "Missing " + strings.Join(missing, ", ")
+	}
+}
+
+func sameURL(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+func seoScore(checks []types.SEOCheck) int {
+	var earned, total float64
+	for _, check := range checks {
+		total += check.Weight
+		switch check.Status {
+		case types.CheckPass:
+			earned += check.Weight
+		case types.CheckWarn:
+			earned += check.Weight / 2
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(earned / total * 100))
 }
@@ -0,0 +1,303 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// gridAdherenceThreshold is the share of on-grid values needed to call a unit a grid.
+	gridAdherenceThreshold = 0.8
+	// gutterTolerance is the spread in pixels below which gaps count as equal.
+	gutterTolerance = 2.0
+	// maxMisalignment is the largest offset treated as an accident rather than intent.
+	maxMisalignment = 8.0
+)
+
+// gridUnits are the candidate spacing units, largest first so the coarsest fitting grid wins.
+var gridUnits = []int{8, 6, 5, 4}
+
+type rawSpacingValue struct {
+	Value    float64 `json:"value"`
+	Margin   int     `json:"margin"`
+	Padding  int     `json:"padding"`
+	Gap      int     `json:"gap"`
+	Selector string  `json:"selector"`
+}
+
+type rawContainer struct {
+	Selector string       `json:"selector"`
+	Rect     types.Rect   `json:"rect"`
+	Children []rawSibling `json:"children"`
+}
+
+type rawSibling struct {
+	Selector string     `json:"selector"`
+	Rect     types.Rect `json:"rect"`
+}
+
+type rawSpacing struct {
+	Values     []rawSpacingValue `json:"values"`
+	Containers []rawContainer    `json:"containers"`
+}
+
+// AnalyzeSpacing collects computed margins, paddings and gaps to detect the spacing scale
+// and off-grid values, and compares the rendered positions of siblings to find uneven
+// gutters and elements that are slightly out of line.
+func AnalyzeSpacing(ctx context.Context) (*types.SpacingAudit, error) {
+	fmt.Println("Analyzing spacing and layout...")
+	var raw rawSpacing
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const values = {};
+			const record = (value, kind, el) => {
+				value = Math.abs(parseFloat(value));
+				if (!isFinite(value) || value < 2) return;
+				value = Math.round(value * 2) / 2;
+				const entry = values[value] || (values[value] = { value, margin: 0, padding: 0, gap: 0, selector: cssPath(el) });
+				entry[kind]++;
+			};
+
+			// Computed margins are resolved to px, so auto margins show up as the leftover width.
+			// Equal side margins that fill the parent around the element are taken as auto.
+			const autoMargins = (el, style) => {
+				const left = parseFloat(style.marginLeft);
+				const right = parseFloat(style.marginRight);
+				const parent = el.parentElement;
+				if (!parent || !(left > 0) || Math.abs(left - right) > 1) return false;
+				const parentStyle = window.getComputedStyle(parent);
+				const inner = parent.clientWidth - parseFloat(parentStyle.paddingLeft) - parseFloat(parentStyle.paddingRight);
+				return Math.abs(el.getBoundingClientRect().width + left + right - inner) <= 1;
+			};
+
+			const containers = [];
+			document.querySelectorAll('body, body *').forEach(el => {
+				if (['SCRIPT', 'STYLE', 'NOSCRIPT', 'BR'].includes(el.tagName) || !isVisible(el)) return;
+				const style = window.getComputedStyle(el);
+
+				const centered = autoMargins(el, style);
+				['Top', 'Right', 'Bottom', 'Left'].forEach(side => {
+					if (!centered || side === 'Top' || side === 'Bottom') record(style['margin' + side], 'margin', el);
+					record(style['padding' + side], 'padding', el);
+				});
+				if (/(flex|grid)/.test(style.display)) {
+					if (style.rowGap !== 'normal') record(style.rowGap, 'gap', el);
+					if (style.columnGap !== 'normal') record(style.columnGap, 'gap', el);
+				}
+
+				const children = Array.from(el.children).filter(child => {
+					if (!isVisible(child)) return false;
+					const childStyle = window.getComputedStyle(child);
+					return childStyle.position !== 'absolute' && childStyle.position !== 'fixed' && childStyle.display !== 'inline';
+				});
+				if (children.length >= 2 && containers.length < 500) {
+					containers.push({
+						selector: cssPath(el),
+						rect: rectOf(el),
+						children: children.map(child => ({ selector: cssPath(child), rect: rectOf(child) }))
+					});
+				}
+			});
+
+			return { values: Object.values(values), containers };
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSpacingAudit(raw), nil
+}
+
+func buildSpacingAudit(raw rawSpacing) *types.SpacingAudit {
+	audit := &types.SpacingAudit{
+		Scale:               []types.SpacingValue{},
+		OffGrid:             []types.SpacingValue{},
+		InconsistentGutters: []types.GutterIssue{},
+		MisalignedSiblings:  []types.AlignmentIssue{},
+	}
+
+	total := 0
+	for _, v := range raw.Values {
+		value := types.SpacingValue{
+			Value: v.Value, Margin: v.Margin, Padding: v.Padding, Gap: v.Gap,
+			Count: v.Margin + v.Padding + v.Gap, Selector: v.Selector,
+		}
+		total += value.Count
+		audit.Scale = append(audit.Scale, value)
+	}
+	sort.Slice(audit.Scale, func(i, j int) bool { return audit.Scale[i].Value < audit.Scale[j].Value })
+	audit.DistinctValues = len(audit.Scale)
+
+	if total > 0 {
+		bestAdherence := -1.0
+		for _, unit := range gridUnits {
+			onGrid := 0
+			for _, v := range audit.Scale {
+				if isOnGrid(v.Value, unit) {
+					onGrid += v.Count
+				}
+			}
+			adherence := float64(onGrid) / float64(total)
+			if adherence >= gridAdherenceThreshold {
+				audit.GridUnit, audit.GridAdherence, audit.OnGrid = unit, round2(adherence), true
+				break
+			}
+			if adherence > bestAdherence {
+				bestAdherence = adherence
+				audit.GridUnit, audit.GridAdherence = unit, round2(adherence)
+			}
+		}
+		for _, v := range audit.Scale {
+			if !isOnGrid(v.Value, audit.GridUnit) {
+				audit.OffGrid = append(audit.OffGrid, v)
+			}
+		}
+		sort.SliceStable(audit.OffGrid, func(i, j int) bool { return audit.OffGrid[i].Count > audit.OffGrid[j].Count })
+	}
+
+	for _, container := range raw.Containers {
+		axis, ordered := siblingAxis(container.Children)
+		if axis == "" {
+			continue
+		}
+		if len(ordered) >= 3 {
+			if issue, ok := checkGutters(container, axis, ordered); ok {
+				audit.InconsistentGutters = append(audit.InconsistentGutters, issue)
+			}
+		}
+		audit.MisalignedSiblings = append(audit.MisalignedSiblings, checkAlignment(container, axis, ordered)...)
+	}
+
+	return audit
+}
+
+func isOnGrid(value float64, unit int) bool {
+	if unit == 0 {
+		return false
+	}
+	remainder := math.Mod(value, float64(unit))
+	return remainder <= 0.5 || float64(unit)-remainder <= 0.5
+}
+
+// siblingAxis reports whether the children form a single row or column and returns them
+// in reading order. Wrapping grids and overlapping layouts return an empty axis.
+func siblingAxis(children []rawSibling) (string, []rawSibling) {
+	ordered := append([]rawSibling(nil), children...)
+
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Rect.X < ordered[j].Rect.X })
+	if isSequence(ordered, func(r types.Rect) (float64, float64) { return r.X, r.X + r.Width },
+		func(r types.Rect) (float64, float64) { return r.Y, r.Y + r.Height }) {
+		return "row", ordered
+	}
+
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Rect.Y < ordered[j].Rect.Y })
+	if isSequence(ordered, func(r types.Rect) (float64, float64) { return r.Y, r.Y + r.Height },
+		func(r types.Rect) (float64, float64) { return r.X, r.X + r.Width }) {
+		return "column", ordered
+	}
+	return "", nil
+}
+
+// isSequence checks that consecutive children do not overlap along the main axis and
+// do overlap along the cross axis.
+func isSequence(children []rawSibling, main, cross func(types.Rect) (float64, float64)) bool {
+	for i := 1; i < len(children); i++ {
+		_, prevEnd := main(children[i-1].Rect)
+		start, _ := main(children[i].Rect)
+		if start < prevEnd-1 {
+			return false
+		}
+		a0, a1 := cross(children[i-1].Rect)
+		b0, b1 := cross(children[i].Rect)
+		if math.Min(a1, b1) <= math.Max(a0, b0) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkGutters(container rawContainer, axis string, children []rawSibling) (types.GutterIssue, bool) {
+	gaps := make([]float64, 0, len(children)-1)
+	low, high := math.Inf(1), math.Inf(-1)
+	for i := 1; i < len(children); i++ {
+		prev, next := children[i-1].Rect, children[i].Rect
+		gap := next.X - (prev.X + prev.Width)
+		if axis == "column" {
+			gap = next.Y - (prev.Y + prev.Height)
+		}
+		gap = math.Round(gap*2) / 2
+		gaps = append(gaps, gap)
+		low, high = math.Min(low, gap), math.Max(high, gap)
+	}
+	if high-low <= gutterTolerance {
+		return types.GutterIssue{}, false
+	}
+	return types.GutterIssue{
+		Selector: container.Selector,
+		Axis:     axis,
+		Gaps:     gaps,
+		Message:  fmt.Sprintf("Gaps between %d children in a %s range from %.0fpx to %.0fpx", len(children), axis, low, high),
+		Rect:     container.Rect,
+	}, true
+}
+
+// checkAlignment finds the alignment line most siblings share across the main axis (start,
+// center or end) and reports children that miss it by a few pixels.
+func checkAlignment(container rawContainer, axis string, children []rawSibling) []types.AlignmentIssue {
+	if len(children) < 3 {
+		return nil
+	}
+	span := func(r types.Rect) (float64, float64) { return r.X, r.Width }
+	names := [3]string{"left", "center", "right"}
+	if axis == "row" {
+		span = func(r types.Rect) (float64, float64) { return r.Y, r.Height }
+		names = [3]string{"top", "middle", "bottom"}
+	}
+	lines := func(r types.Rect) [3]float64 {
+		start, size := span(r)
+		return [3]float64{math.Round(start), math.Round(start + size/2), math.Round(start + size)}
+	}
+
+	line, shared, best := 0, 0.0, 0
+	for i := range names {
+		counts := make(map[float64]int)
+		for _, child := range children {
+			counts[lines(child.Rect)[i]]++
+		}
+		for position, count := range counts {
+			if count > best {
+				line, shared, best = i, position, count
+			}
+		}
+	}
+	if best < 2 || best*2 < len(children) {
+		return nil
+	}
+
+	var issues []types.AlignmentIssue
+	for _, child := range children {
+		offset := lines(child.Rect)[line] - shared
+		if offset == 0 || math.Abs(offset) > maxMisalignment {
+			continue
+		}
+		issues = append(issues, types.AlignmentIssue{
+			Parent:   container.Selector,
+			Selector: child.Selector,
+			Edge:     names[line],
+			Offset:   offset,
+			Message:  fmt.Sprintf("%+.0fpx off the %s line shared by %d siblings", offset, names[line], best),
+			Rect:     child.Rect,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,495 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// schemaRule lists the properties Google expects for a schema.org type to produce a rich result.
+type schemaRule struct {
+	Required    []string
+	AnyOf       []string // At least one of these must be present
+	Recommended []string
+	RichResult  string
+	Check       func(item map[string]interface{}) []string
+}
+
+var schemaRules = map[string]schemaRule{
+	"Organization": {
+		Required:    []string{"name"},
+		Recommended: []string{"url", "logo", "sameAs", "contactPoint"},
+		RichResult:  "Organization logo",
+	},
+	"Product": {
+		Required:    []string{"name"},
+		AnyOf:       []string{"offers", "review", "aggregateRating"},
+		Recommended: []string{"image", "description", "sku", "brand"},
+		RichResult:  "Product snippet",
+		Check:       checkProduct,
+	},
+	"Article": {
+		Required:    []string{"headline"},
+		Recommended: []string{"image", "datePublished", "dateModified", "author"},
+		RichResult:  "Article",
+	},
+	"BreadcrumbList": {
+		Required:   []string{"itemListElement"},
+		RichResult: "Breadcrumb",
+		Check:      checkBreadcrumbs,
+	},
+	"FAQPage": {
+		Required:   []string{"mainEntity"},
+		RichResult: "FAQ",
+		Check:      checkFAQ,
+	},
+	"LocalBusiness": {
+		Required:    []string{"name", "address"},
+		Recommended: []string{"telephone", "url", "image", "geo", "openingHoursSpecification", "priceRange"},
+		RichResult:  "Local business",
+	},
+}
+
+// schemaAliases maps common subtypes to the validated parent type.
+var schemaAliases = map[string]string{
+	"Corporation":         "Organization",
+	"NGO":                 "Organization",
+	"NewsArticle":         "Article",
+	"BlogPosting":         "Article",
+	"TechArticle":         "Article",
+	"Report":              "Article",
+	"ProductGroup":        "Product",
+	"Restaurant":          "LocalBusiness",
+	"Store":               "LocalBusiness",
+	"Dentist":             "LocalBusiness",
+	"MedicalBusiness":     "LocalBusiness",
+	"ProfessionalService": "LocalBusiness",
+	"FoodEstablishment":   "LocalBusiness",
+	"LodgingBusiness":     "LocalBusiness",
+	"AutomotiveBusiness":  "LocalBusiness",
+}
+
+var schemaPrefixes = []string{"https://schema.org/", "http://schema.org/", "schema:"}
+
+type rawStructuredData struct {
+	JSONLD    []string                 `json:"jsonLd"`
+	Microdata []map[string]interface{} `json:"microdata"`
+	RDFa      []map[string]interface{} `json:"rdfa"`
+}
+
+// AnalyzeStructuredData extracts JSON-LD, Microdata and RDFa items, normalizes them into
+// property trees and validates the schema.org types that are eligible for rich results.
+func AnalyzeStructuredData(ctx context.Context) (*types.StructuredDataAudit, error) {
+	fmt.Println("Analyzing structured data...")
+	var raw rawStructuredData
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {
+			const MAX_DEPTH = 6;
+
+			function microdataValue(el) {
+				const tag = el.tagName;
+				if (tag === 'META') return el.getAttribute('content') || '';
+				if (['A', 'AREA', 'LINK'].includes(tag)) return el.href || '';
+				if (['IMG', 'AUDIO', 'VIDEO', 'SOURCE', 'IFRAME', 'EMBED', 'TRACK'].includes(tag)) return el.src || '';
+				if (tag === 'OBJECT') return el.data || '';
+				if (tag === 'DATA' || tag === 'METER') return el.getAttribute('value') || '';
+				if (tag === 'TIME') return el.getAttribute('datetime') || el.textContent.trim();
+				return (el.textContent || '').replace(/\s+/g, ' ').trim();
+			}
+
+			function rdfaValue(el) {
+				if (el.hasAttribute('content')) return el.getAttribute('content');
+				if (el.hasAttribute('resource')) return el.getAttribute('resource');
+				if (el.hasAttribute('datetime')) return el.getAttribute('datetime');
+				if (el.hasAttribute('href')) return el.href;
+				if (el.hasAttribute('src')) return el.src;
+				return (el.textContent || '').replace(/\s+/g, ' ').trim();
+			}
+
+			// Build a {"@type": ..., prop: [values]} tree for an item element. Properties belong
+			// to the nearest enclosing scope, so nested items keep their own properties.
+			function buildItem(scope, scopeAttr, typeAttr, propAttr, valueOf, depth) {
+				const item = { '@type': (scope.getAttribute(typeAttr) || '').trim() };
+				if (scope.hasAttribute('vocab')) item['@vocab'] = scope.getAttribute('vocab');
+				if (depth >= MAX_DEPTH) return item;
+				scope.querySelectorAll('[' + propAttr + ']').forEach(el => {
+					const owner = el.parentElement ? el.parentElement.closest('[' + scopeAttr + ']') : null;
+					if (owner !== scope) return;
+					const value = el.hasAttribute(scopeAttr)
+						? buildItem(el, scopeAttr, typeAttr, propAttr, valueOf, depth + 1)
+						: valueOf(el);
+					el.getAttribute(propAttr).trim().split(/\s+/).forEach(name => {
+						(item[name] = item[name] || []).push(value);
+					});
+				});
+				return item;
+			}
+
+			return {
+				jsonLd: Array.from(document.querySelectorAll('script[type="application/ld+json"]'))
+					.map(script => script.textContent || ''),
+				microdata: Array.from(document.querySelectorAll('[itemscope]:not([itemprop])'))
+					.map(el => buildItem(el, 'itemscope', 'itemtype', 'itemprop', microdataValue, 0)),
+				rdfa: Array.from(document.querySelectorAll('[typeof]:not([property])'))
+					.map(el => buildItem(el, 'typeof', 'typeof', 'property', rdfaValue, 0))
+			};
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildStructuredDataAudit(raw), nil
+}
+
+func buildStructuredDataAudit(raw rawStructuredData) *types.StructuredDataAudit {
+	audit := &types.StructuredDataAudit{
+		Counts:              map[string]int{"json-ld": 0, "microdata": 0, "rdfa": 0},
+		Items:               []types.StructuredDataItem{},
+		ParseErrors:         []types.StructuredDataError{},
+		EligibleRichResults: []string{},
+	}
+
+	var nodes []struct {
+		format string
+		node   map[string]interface{}
+	}
+	add := func(format string, node map[string]interface{}) {
+		nodes = append(nodes, struct {
+			format string
+			node   map[string]interface{}
+		}{format, node})
+	}
+
+	for _, block := range raw.JSONLD {
+		trimmed := strings.TrimSpace(block)
+		if trimmed == "" {
+			audit.ParseErrors = append(audit.ParseErrors, types.StructuredDataError{
+				Format: "json-ld", Message: "Empty JSON-LD block",
+			})
+			continue
+		}
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
+			audit.ParseErrors = append(audit.ParseErrors, types.StructuredDataError{
+				Format: "json-ld", Message: jsonErrorMessage(trimmed, err), Snippet: truncate(trimmed, 120),
+			})
+			continue
+		}
+		for _, node := range jsonLDNodes(parsed) {
+			add("json-ld", node)
+		}
+	}
+	for _, node := range raw.Microdata {
+		add("microdata", node)
+	}
+	for _, node := range raw.RDFa {
+		add("rdfa", node)
+	}
+
+	eligible := make(map[string]bool)
+	for _, n := range nodes {
+		properties, _ := normalizeStructuredValue(n.node).(map[string]interface{})
+		itemType := primaryType(properties["@type"])
+		if itemType == "" {
+			snippet, _ := json.Marshal(properties)
+			audit.ParseErrors = append(audit.ParseErrors, types.StructuredDataError{
+				Format: n.format, Message: "Item has no @type", Snippet: truncate(string(snippet), 120),
+			})
+			continue
+		}
+		item := validateStructuredItem(n.format, itemType, properties)
+		audit.Counts[n.format]++
+		if item.Eligible && !eligible[item.RichResult] {
+			eligible[item.RichResult] = true
+			audit.EligibleRichResults = append(audit.EligibleRichResults, item.RichResult)
+		}
+		audit.Items = append(audit.Items, item)
+	}
+	sort.Strings(audit.EligibleRichResults)
+
+	return audit
+}
+
+// jsonLDNodes flattens top-level arrays and @graph containers into individual items.
+func jsonLDNodes(value interface{}) []map[string]interface{} {
+	var nodes []map[string]interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		for _, entry := range v {
+			nodes = append(nodes, jsonLDNodes(entry)...)
+		}
+	case map[string]interface{}:
+		if graph, ok := v["@graph"]; ok {
+			nodes = append(nodes, jsonLDNodes(graph)...)
+		} else {
+			nodes = append(nodes, v)
+		}
+	}
+	return nodes
+}
+
+// normalizeStructuredValue strips vocabulary prefixes from types and property names,
+// drops @context/@vocab and unwraps single-element arrays so all formats share one shape.
+func normalizeStructuredValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if key == "@context" || key == "@vocab" {
+				continue
+			}
+			if key == "@type" {
+				normalized[key] = normalizeTypes(child)
+				continue
+			}
+			normalized[stripSchemaPrefix(key)] = normalizeStructuredValue(child)
+		}
+		return normalized
+	case []interface{}:
+		if len(v) == 1 {
+			return normalizeStructuredValue(v[0])
+		}
+		list := make([]interface{}, len(v))
+		for i, child := range v {
+			list[i] = normalizeStructuredValue(child)
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+// normalizeTypes turns a type value (string, space separated list or array) into a
+// prefix-free string, or a list of them when there is more than one.
+func normalizeTypes(value interface{}) interface{} {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = strings.Fields(v)
+	case []interface{}:
+		for _, entry := range v {
+			if s, ok := entry.(string); ok {
+				names = append(names, strings.Fields(s)...)
+			}
+		}
+	}
+	list := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		list = append(list, stripSchemaPrefix(name))
+	}
+	if len(list) == 1 {
+		return list[0]
+	}
+	return list
+}
+
+func stripSchemaPrefix(name string) string {
+	for _, prefix := range schemaPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// primaryType picks the validated type when an item declares several, else the first one.
+func primaryType(value interface{}) string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = []string{v}
+	case []interface{}:
+		for _, entry := range v {
+			if s, ok := entry.(string); ok {
+				names = append(names, s)
+			}
+		}
+	}
+	for _, name := range names {
+		if _, ok := schemaRules[canonicalType(name)]; ok {
+			return name
+		}
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+func canonicalType(name string) string {
+	if alias, ok := schemaAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+func validateStructuredItem(format, itemType string, properties map[string]interface{}) types.StructuredDataItem {
+	item := types.StructuredDataItem{
+		Format:             format,
+		Type:               itemType,
+		Properties:         properties,
+		MissingRequired:    []string{},
+		MissingRecommended: []string{},
+		Issues:             []string{},
+	}
+
+	rule, ok := schemaRules[canonicalType(itemType)]
+	if !ok {
+		return item
+	}
+	item.Validated = true
+	item.RichResult = rule.RichResult
+
+	for _, name := range rule.Required {
+		if !hasProperty(properties, name) {
+			item.MissingRequired = append(item.MissingRequired, name)
+		}
+	}
+	if len(rule.AnyOf) > 0 {
+		found := false
+		for _, name := range rule.AnyOf {
+			found = found || hasProperty(properties, name)
+		}
+		if !found {
+			item.MissingRequired = append(item.MissingRequired, strings.Join(rule.AnyOf, " | "))
+		}
+	}
+	for _, name := range rule.Recommended {
+		if !hasProperty(properties, name) {
+			item.MissingRecommended = append(item.MissingRecommended, name)
+		}
+	}
+	if rule.Check != nil {
+		item.Issues = append(item.Issues, rule.Check(properties)...)
+	}
+
+	item.Eligible = len(item.MissingRequired) == 0 && len(item.Issues) == 0
+	return item
+}
+
+func checkProduct(item map[string]interface{}) []string {
+	var issues []string
+	for i, offer := range asList(item["offers"]) {
+		o, ok := offer.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !hasProperty(o, "price") && !hasProperty(o, "lowPrice") && !hasProperty(o, "priceSpecification") {
+			issues = append(issues, fmt.Sprintf("offers[%d] has no price", i))
+		}
+		if hasProperty(o, "price") && !hasProperty(o, "priceCurrency") {
+			issues = append(issues, fmt.Sprintf("offers[%d] has no priceCurrency", i))
+		}
+	}
+	return issues
+}
+
+func checkBreadcrumbs(item map[string]interface{}) []string {
+	var issues []string
+	elements := asList(item["itemListElement"])
+	for i, element := range elements {
+		e, ok := element.(map[string]interface{})
+		if !ok {
+			issues = append(issues, fmt.Sprintf("itemListElement[%d] is not a ListItem", i))
+			continue
+		}
+		if !hasProperty(e, "position") {
+			issues = append(issues, fmt.Sprintf("itemListElement[%d] has no position", i))
+		}
+		target, _ := e["item"].(map[string]interface{})
+		if !hasProperty(e, "name") && !hasProperty(target, "name") {
+			issues = append(issues, fmt.Sprintf("itemListElement[%d] has no name", i))
+		}
+		// The last crumb may omit its URL, since it is the current page.
+		if i < len(elements)-1 && !hasProperty(e, "item") {
+			issues = append(issues, fmt.Sprintf("itemListElement[%d] has no item URL", i))
+		}
+	}
+	return issues
+}
+
+func checkFAQ(item map[string]interface{}) []string {
+	var issues []string
+	for i, entity := range asList(item["mainEntity"]) {
+		question, ok := entity.(map[string]interface{})
+		if !ok || !hasType(question, "Question") {
+			issues = append(issues, fmt.Sprintf("mainEntity[%d] is not a Question", i))
+			continue
+		}
+		if !hasProperty(question, "name") {
+			issues = append(issues, fmt.Sprintf("mainEntity[%d] has no question text (name)", i))
+		}
+		answer, _ := question["acceptedAnswer"].(map[string]interface{})
+		if !hasProperty(answer, "text") {
+			issues = append(issues, fmt.Sprintf("mainEntity[%d] has no acceptedAnswer text", i))
+		}
+	}
+	return issues
+}
+
+// hasType reports whether an item declares the given type among its types.
+func hasType(item map[string]interface{}, name string) bool {
+	for _, t := range asList(item["@type"]) {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasProperty reports whether a property is present and not empty.
+func hasProperty(item map[string]interface{}, name string) bool {
+	if item == nil {
+		return false
+	}
+	switch v := item[name].(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+func asList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// jsonErrorMessage adds the line number to syntax errors so broken blocks are easy to find.
+func jsonErrorMessage(block string, err error) string {
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		line := strings.Count(block[:syntaxErr.Offset], "\n") + 1
+		return fmt.Sprintf("Invalid JSON on line %d: %v", line, err)
+	}
+	return "Invalid JSON: " + err.Error()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "..."
+}
@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// minTapTargetSize is the recommended minimum touch target size in CSS pixels.
+const minTapTargetSize = 48.0
+
+type rawTapTarget struct {
+	Selector string     `json:"selector"`
+	Text     string     `json:"text"`
+	Inline   bool       `json:"inline"`
+	Rect     types.Rect `json:"rect"`
+}
+
+type rawTapTargetResult struct {
+	ViewportWidth int64          `json:"viewportWidth"`
+	Targets       []rawTapTarget `json:"targets"`
+}
+
+// AnalyzeTapTargets measures every clickable element in the current (mobile) viewport and
+// flags targets that are too small, too close to their neighbours or overlapping.
+func AnalyzeTapTargets(ctx context.Context, device string) (*types.TapTargetAudit, error) {
+	fmt.Println("Analyzing tap targets...")
+	var raw rawTapTargetResult
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const selector = 'a[href], button, input:not([type="hidden"]), select, textarea, summary, ' +
+				'[role="button"], [role="link"], [role="checkbox"], [role="radio"], [role="tab"], ' +
+				'[role="menuitem"], [role="switch"], [onclick], [tabindex]:not([tabindex="-1"])';
+			const targets = [];
+			document.querySelectorAll(selector).forEach(el => {
+				if (!isVisible(el)) return;
+				const style = window.getComputedStyle(el);
+				// Links inside running text are exempt from target size requirements
+				let inline = false;
+				if (el.tagName === 'A' && style.display === 'inline' && el.parentElement) {
+					const parentText = (el.parentElement.innerText || '').trim();
+					const ownText = (el.innerText || '').trim();
+					inline = parentText.length > ownText.length + 20;
+				}
+				targets.push({
+					selector: cssPath(el),
+					text: accessibleName(el).substring(0, 80),
+					inline,
+					rect: rectOf(el)
+				});
+			});
+			return { viewportWidth: window.innerWidth, targets };
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	audit := buildTapTargetAudit(raw.Targets)
+	audit.Device = device
+	audit.ViewportSize = raw.ViewportWidth
+	return audit, nil
+}
+
+func buildTapTargetAudit(targets []rawTapTarget) *types.TapTargetAudit {
+	audit := &types.TapTargetAudit{
+		MinSize:      minTapTargetSize,
+		TotalTargets: len(targets),
+		Offenders:    []types.TapTarget{},
+	}
+
+	for i, target := range targets {
+		result := types.TapTarget{
+			Selector:        target.Selector,
+			Text:            target.Text,
+			Width:           target.Rect.Width,
+			Height:          target.Rect.Height,
+			NearestDistance: math.Inf(1),
+			Rect:            target.Rect,
+		}
+		small := !target.Inline && (target.Rect.Width < minTapTargetSize || target.Rect.Height < minTapTargetSize)
+		touchArea := expandToMinSize(target.Rect, minTapTargetSize)
+
+		overlapping, tooClose := false, false
+		for j, other := range targets {
+			if i == j || contains(target.Rect, other.Rect) || contains(other.Rect, target.Rect) {
+				continue
+			}
+			distance := rectDistance(target.Rect, other.Rect)
+			if distance < result.NearestDistance {
+				result.NearestDistance = distance
+				result.NearestSelector = other.Selector
+			}
+			if intersects(target.Rect, other.Rect) {
+				overlapping = true
+			} else if small && intersects(touchArea, other.Rect) {
+				tooClose = true
+			}
+		}
+		if math.IsInf(result.NearestDistance, 1) {
+			result.NearestDistance = 0
+		}
+
+		if small {
+			audit.TooSmall++
+			result.Issues = append(result.Issues, fmt.Sprintf("Too small: %.0fx%.0f px (minimum %.0fx%.0f)",
+				target.Rect.Width, target.Rect.Height, minTapTargetSize, minTapTargetSize))
+		}
+		if tooClose {
+			audit.TooClose++
+			result.Issues = append(result.Issues, fmt.Sprintf("Too close to %s (%.0f px apart)",
+				result.NearestSelector, result.NearestDistance))
+		}
+		if overlapping {
+			audit.Overlapping++
+			result.Issues = append(result.Issues, "Overlaps another tap target")
+		}
+		if len(result.Issues) > 0 {
+			audit.Offenders = append(audit.Offenders, result)
+		}
+	}
+
+	return audit
+}
+
+// expandToMinSize grows a rect around its center so that it is at least size x size.
+func expandToMinSize(r types.Rect, size float64) types.Rect {
+	if r.Width < size {
+		r.X -= (size - r.Width) / 2
+		r.Width = size
+	}
+	if r.Height < size {
+		r.Y -= (size - r.Height) / 2
+		r.Height = size
+	}
+	return r
+}
+
+func intersects(a, b types.Rect) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+func contains(outer, inner types.Rect) bool {
+	return inner.X >= outer.X && inner.Y >= outer.Y &&
+		inner.X+inner.Width <= outer.X+outer.Width && inner.Y+inner.Height <= outer.Y+outer.Height
+}
+
+// rectDistance returns the shortest gap between two rects, 0 if they touch or overlap.
+func rectDistance(a, b types.Rect) float64 {
+	dx := math.Max(0, math.Max(b.X-(a.X+a.Width), a.X-(b.X+b.Width)))
+	dy := math.Max(0, math.Max(b.Y-(a.Y+a.Height), a.Y-(b.Y+b.Height)))
+	return math.Hypot(dx, dy)
+}
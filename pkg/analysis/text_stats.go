@@ -0,0 +1,193 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// languageProfile holds the language-specific parts of the readability metrics.
+type languageProfile struct {
+	Code           string
+	Vowels         string
+	WordsPerMinute float64 // Average silent reading speed (Trauzettel-Klosinski et al., 2012)
+	EaseFormula    string
+	ReadingEase    func(wordsPerSentence, syllablesPerWord float64) float64
+	Stopwords      []string
+	Passive        func(sentence string) bool
+}
+
+var (
+	englishPassive = regexp.MustCompile(`(?i)\b(?:am|is|are|was|were|be|been|being|gets?|got|gotten)\s+(?:\w+ly\s+)?` +
+		`(?:\w+ed|\w+wn|born|worn|torn|made|done|built|taken|given|known|shown|seen|found|held|kept|left|lost|paid|put|said|sent|sold|` +
+		`told|thought|brought|bought|caught|taught|written|chosen|spoken|driven|eaten|broken|forgotten|hidden)\b`)
+	spanishPassive = regexp.MustCompile(`(?i)(?:^|[^\p{L}])(?:es|son|fue|fueron|era|eran|ser|sido|será|serán|sea|sean)\s+` +
+		`(?:\p{L}+mente\s+)?\p{L}+(?:ado|ada|ados|adas|ido|ida|idos|idas)(?:$|[^\p{L}])`)
+	germanPassiveAux = regexp.MustCompile(`(?i)(?:^|[^\p{L}])(?:wird|werden|wurde|wurden|worden|werde|wirst|würde|würden)(?:$|[^\p{L}])`)
+	germanParticiple = regexp.MustCompile(`(?i)(?:^|[^\p{L}])ge\p{L}{2,}(?:t|en)(?:$|[^\p{L}])`)
+	frenchPassive    = regexp.MustCompile(`(?i)(?:^|[^\p{L}])(?:est|sont|était|étaient|fut|furent|été|être|sera|seront|soit|soient)\s+` +
+		`(?:\p{L}+ment\s+)?\p{L}+(?:é|ée|és|ées|is|ise|ises|it|ite|its|ites|u|ue|us|ues)(?:$|[^\p{L}])`)
+)
+
+var languageProfiles = map[string]languageProfile{
+	"en": {
+		Code:           "en",
+		Vowels:         "aeiouy",
+		WordsPerMinute: 228,
+		EaseFormula:    "Flesch (English)",
+		ReadingEase: func(wps, spw float64) float64 {
+			return 206.835 - 1.015*wps - 84.6*spw
+		},
+		Stopwords: []string{"the", "and", "of", "to", "is", "in", "that", "it", "for", "with", "you", "this", "are", "on", "was"},
+		Passive:   englishPassive.MatchString,
+	},
+	"es": {
+		Code:           "es",
+		Vowels:         "aeiouáéíóúü",
+		WordsPerMinute: 218,
+		EaseFormula:    "Szigriszt-Pazos (Spanish)",
+		ReadingEase: func(wps, spw float64) float64 {
+			return 206.835 - 62.3*spw - wps
+		},
+		Stopwords: []string{"el", "la", "de", "que", "y", "en", "los", "del", "las", "por", "un", "una", "con", "para", "es"},
+		Passive:   spanishPassive.MatchString,
+	},
+	"de": {
+		Code:           "de",
+		Vowels:         "aeiouyäöü",
+		WordsPerMinute: 179,
+		EaseFormula:    "Amstad (German)",
+		ReadingEase: func(wps, spw float64) float64 {
+			return 180 - wps - 58.5*spw
+		},
+		Stopwords: []string{"der", "die", "und", "das", "ist", "nicht", "mit", "den", "ein", "eine", "zu", "sie", "auf", "für", "sich"},
+		Passive: func(sentence string) bool {
+			return germanPassiveAux.MatchString(sentence) && germanParticiple.MatchString(sentence)
+		},
+	},
+	"fr": {
+		Code:           "fr",
+		Vowels:         "aeiouyàâéèêëîïôûùü",
+		WordsPerMinute: 195,
+		EaseFormula:    "Kandel-Moles (French)",
+		ReadingEase: func(wps, spw float64) float64 {
+			return 207 - 1.015*wps - 73.6*spw
+		},
+		Stopwords: []string{"le", "la", "les", "et", "des", "est", "une", "du", "que", "pour", "dans", "qui", "pas", "sur", "au"},
+		Passive:   frenchPassive.MatchString,
+	},
+}
+
+var (
+	wordPattern     = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’-][\p{L}\p{N}]+)*`)
+	sentencePattern = regexp.MustCompile(`[^.!?…]+(?:[.!?…]+["'»”)\]]*|$)`)
+)
+
+// resolveLanguage picks a supported language from the lang attribute, falling back to a
+// stopword vote over the text. The second result says which source was used.
+func resolveLanguage(langAttr string, words []string) (languageProfile, string) {
+	code := strings.ToLower(strings.TrimSpace(langAttr))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if profile, ok := languageProfiles[code]; ok {
+		return profile, "lang attribute"
+	}
+
+	best, bestHits := languageProfiles["en"], 0
+	for _, code := range []string{"en", "es", "de", "fr"} {
+		profile := languageProfiles[code]
+		stop := make(map[string]bool, len(profile.Stopwords))
+		for _, w := range profile.Stopwords {
+			stop[w] = true
+		}
+		hits := 0
+		for i, w := range words {
+			if i >= 2000 {
+				break
+			}
+			if stop[strings.ToLower(w)] {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = profile, hits
+		}
+	}
+	return best, "detected"
+}
+
+// splitSentences splits a block of text into sentences on terminal punctuation.
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, s := range sentencePattern.FindAllString(text, -1) {
+		if wordPattern.MatchString(s) {
+			sentences = append(sentences, strings.TrimSpace(s))
+		}
+	}
+	return sentences
+}
+
+// countSyllables estimates syllables by counting vowel groups, with the silent endings of
+// English and French taken into account.
+func countSyllables(word string, profile languageProfile) int {
+	w := strings.ToLower(word)
+	groups := 0
+	inVowel := false
+	for _, r := range w {
+		isVowel := strings.ContainsRune(profile.Vowels, r)
+		if isVowel && !inVowel {
+			groups++
+		}
+		inVowel = isVowel
+	}
+
+	switch profile.Code {
+	case "en":
+		if groups > 1 && strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "le") && !strings.HasSuffix(w, "ee") {
+			groups--
+		} else if groups > 1 && strings.HasSuffix(w, "ed") && !strings.HasSuffix(w, "ted") && !strings.HasSuffix(w, "ded") {
+			groups--
+		}
+	case "fr":
+		if groups > 1 && (strings.HasSuffix(w, "e") || strings.HasSuffix(w, "es")) {
+			groups--
+		}
+	}
+
+	if groups == 0 {
+		return 1
+	}
+	return groups
+}
+
+// letterCount counts letters and digits, ignoring apostrophes and hyphens.
+func letterCount(word string) int {
+	n := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// readingEaseLevel maps a Flesch-style score to the usual difficulty labels.
+func readingEaseLevel(score float64) string {
+	switch {
+	case score >= 90:
+		return "Very easy"
+	case score >= 80:
+		return "Easy"
+	case score >= 70:
+		return "Fairly easy"
+	case score >= 60:
+		return "Standard"
+	case score >= 50:
+		return "Fairly difficult"
+	case score >= 30:
+		return "Difficult"
+	default:
+		return "Very difficult"
+	}
+}
@@ -0,0 +1,165 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+)
+
+// tokenGroup ties a token list to its name in each export format.
+type tokenGroup struct {
+	W3CPath  []string // Nested group path in the W3C JSON
+	CSS      string   // Custom property prefix
+	Tailwind string   // theme.extend key
+	Tokens   []types.DesignToken
+}
+
+func tokenGroups(tokens *types.DesignTokens) []tokenGroup {
+	return []tokenGroup{
+		{[]string{"color"}, "color", "colors", tokens.Colors},
+		{[]string{"font", "family"}, "font-family", "fontFamily", tokens.FontFamilies},
+		{[]string{"font", "size"}, "font-size", "fontSize", tokens.FontSizes},
+		{[]string{"font", "weight"}, "font-weight", "fontWeight", tokens.FontWeights},
+		{[]string{"spacing"}, "spacing", "spacing", tokens.Spacing},
+		{[]string{"radius"}, "radius", "borderRadius", tokens.Radii},
+		{[]string{"shadow"}, "shadow", "boxShadow", tokens.Shadows},
+	}
+}
+
+// exportW3CTokens renders the tokens in the W3C Design Tokens Community Group format.
+func exportW3CTokens(tokens *types.DesignTokens) string {
+	root := make(map[string]interface{})
+	for _, group := range tokenGroups(tokens) {
+		if len(group.Tokens) == 0 {
+			continue
+		}
+		node := root
+		for _, key := range group.W3CPath {
+			child, ok := node[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[key] = child
+			}
+			node = child
+		}
+		for _, token := range group.Tokens {
+			node[token.Name] = map[string]interface{}{
+				"$type":  token.Type,
+				"$value": w3cValue(token),
+			}
+		}
+	}
+
+	out, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// w3cValue converts a CSS value into the value shape the W3C format expects for its type.
+func w3cValue(token types.DesignToken) interface{} {
+	switch token.Type {
+	case "fontFamily":
+		if family, err := strconv.Unquote(token.Value); err == nil {
+			return []string{family}
+		}
+		return []string{token.Value}
+	case "fontWeight":
+		if weight, err := strconv.Atoi(token.Value); err == nil {
+			return weight
+		}
+	case "shadow":
+		var layers []map[string]interface{}
+		for _, layer := range splitTopLevel(token.Value) {
+			layers = append(layers, w3cShadowLayer(layer))
+		}
+		if len(layers) == 1 {
+			return layers[0]
+		}
+		return layers
+	}
+	return token.Value
+}
+
+// w3cShadowLayer splits a computed shadow such as "rgba(0, 0, 0, 0.1) 0px 1px 3px 0px"
+// into its color and lengths.
+func w3cShadowLayer(layer string) map[string]interface{} {
+	color := layer
+	if open := strings.Index(layer, "("); open >= 0 {
+		if end := strings.Index(layer[open:], ")"); end >= 0 {
+			color = layer[:open+end+1]
+			if start := strings.LastIndexAny(layer[:open], " "); start >= 0 {
+				color = layer[start+1 : open+end+1]
+			}
+		}
+	}
+	if c, ok := parseCSSColor(color); ok && c.A == 1 {
+		color = c.hex()
+	}
+
+	lengths := lengthPattern.FindAllString(layer, -1)
+	for len(lengths) < 4 {
+		lengths = append(lengths, "0px")
+	}
+	shadow := map[string]interface{}{
+		"color":   color,
+		"offsetX": lengths[0],
+		"offsetY": lengths[1],
+		"blur":    lengths[2],
+		"spread":  lengths[3],
+	}
+	if strings.Contains(layer, "inset") {
+		shadow["inset"] = true
+	}
+	return shadow
+}
+
+// exportCSSTokens renders the tokens as custom properties on :root.
+func exportCSSTokens(tokens *types.DesignTokens) string {
+	var b strings.Builder
+	b.WriteString(":root {\n")
+	for _, group := range tokenGroups(tokens) {
+		for _, token := range group.Tokens {
+			fmt.Fprintf(&b, "  --%s-%s: %s;\n", group.CSS, cssIdent(token.Name), token.Value)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// exportTailwindTokens renders the tokens as a theme.extend block for tailwind.config.js.
+func exportTailwindTokens(tokens *types.DesignTokens) string {
+	var b strings.Builder
+	b.WriteString("module.exports = {\n  theme: {\n    extend: {\n")
+	for _, group := range tokenGroups(tokens) {
+		if len(group.Tokens) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "      %s: {\n", group.Tailwind)
+		for _, token := range group.Tokens {
+			value := jsString(token.Value)
+			if token.Type == "fontFamily" {
+				if family, err := strconv.Unquote(token.Value); err == nil {
+					value = "[" + jsString(family) + "]"
+				}
+			}
+			fmt.Fprintf(&b, "        %s: %s,\n", jsString(token.Name), value)
+		}
+		b.WriteString("      },\n")
+	}
+	b.WriteString("    },\n  },\n};\n")
+	return b.String()
+}
+
+// cssIdent makes a token name safe for use in a custom property name ("0.5" -> "0_5").
+func cssIdent(name string) string {
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+func jsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,367 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Recommended limits for a coherent type system.
+const (
+	maxFontSizes    = 8
+	maxFontWeights  = 4
+	maxFontFamilies = 3
+	minLineHeight   = 1.4
+	minCharsPerLine = 45
+	maxCharsPerLine = 80
+	// scaleFitTolerance is the mean relative deviation up to which sizes follow a ratio.
+	scaleFitTolerance = 0.04
+)
+
+// modularRatios are the classic type scale ratios, largest first.
+var modularRatios = []struct {
+	Name  string
+	Ratio float64
+}{
+	{"Golden Ratio", 1.618},
+	{"Perfect Fifth", 1.5},
+	{"Augmented Fourth", 1.414},
+	{"Perfect Fourth", 1.333},
+	{"Major Third", 1.25},
+	{"Minor Third", 1.2},
+	{"Major Second", 1.125},
+	{"Minor Second", 1.067},
+}
+
+type rawTypeElement struct {
+	Tag        string  `json:"tag"`
+	Selector   string  `json:"selector"`
+	FontSize   float64 `json:"fontSize"`
+	FontWeight string  `json:"fontWeight"`
+	Family     string  `json:"family"`
+	LineHeight float64 `json:"lineHeight"`
+	Chars      int     `json:"chars"`
+	Body       bool    `json:"body"`
+	CharsLine  float64 `json:"charsPerLine"`
+}
+
+type rawTypography struct {
+	Elements       []rawTypeElement     `json:"elements"`
+	FontFaces      []types.FontFaceInfo `json:"fontFaces"`
+	FontFiles      int                  `json:"fontFiles"`
+	FontBytes      int64                `json:"fontBytes"`
+	PreloadedFonts int                  `json:"preloadedFonts"`
+}
+
+// AnalyzeTypography infers the type scale and checks it against the classic modular
+// ratios, counts sizes/weights/families, measures body line height and line length,
+// and inspects how web fonts are loaded.
+func AnalyzeTypography(ctx context.Context) (*types.TypographyAudit, error) {
+	fmt.Println("Analyzing typography...")
+	var raw rawTypography
+	err := chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`
+		(function() {`+domHelpersJS+`
+			const canvas = document.createElement('canvas').getContext('2d');
+			const bodyTags = ['P', 'LI', 'DD', 'BLOCKQUOTE', 'TD'];
+			const elements = [];
+
+			document.querySelectorAll('body *').forEach(el => {
+				if (['SCRIPT', 'STYLE', 'NOSCRIPT', 'SVG'].includes(el.tagName) || !isVisible(el)) return;
+				let chars = 0;
+				el.childNodes.forEach(node => {
+					if (node.nodeType === Node.TEXT_NODE) chars += node.textContent.replace(/\s+/g, ' ').trim().length;
+				});
+				if (chars === 0) return;
+
+				const style = window.getComputedStyle(el);
+				const fontSize = parseFloat(style.fontSize) || 0;
+				const lineHeight = style.lineHeight === 'normal' ? fontSize * 1.2 : parseFloat(style.lineHeight) || 0;
+				const text = (el.innerText || '').replace(/\s+/g, ' ').trim();
+				const body = bodyTags.includes(el.tagName) && text.length >= 80;
+
+				// Characters per line: content box width over the average glyph width of this text.
+				let charsPerLine = 0;
+				if (body) {
+					canvas.font = style.fontStyle + ' ' + style.fontWeight + ' ' + style.fontSize + ' ' + style.fontFamily;
+					const sample = text.substring(0, 500);
+					const average = canvas.measureText(sample).width / sample.length;
+					const width = el.clientWidth - parseFloat(style.paddingLeft) - parseFloat(style.paddingRight);
+					if (average > 0) charsPerLine = width / average;
+				}
+
+				elements.push({
+					tag: el.tagName.toLowerCase(),
+					selector: cssPath(el),
+					fontSize,
+					fontWeight: style.fontWeight,
+					family: style.fontFamily.split(',')[0].replace(/["']/g, '').trim(),
+					lineHeight: fontSize > 0 ? lineHeight / fontSize : 0,
+					chars,
+					body,
+					charsPerLine
+				});
+			});
+
+			const fontFaces = [];
+			if (document.fonts) {
+				document.fonts.forEach(face => fontFaces.push({
+					family: face.family.replace(/["']/g, ''),
+					weight: face.weight,
+					style: face.style,
+					display: face.display || 'auto',
+					status: face.status
+				}));
+			}
+
+			const fontFiles = performance.getEntriesByType('resource')
+				.filter(entry => /\.(woff2?|ttf|otf|eot)(\?|#|$)/i.test(entry.name));
+
+			return {
+				elements,
+				fontFaces,
+				fontFiles: fontFiles.length,
+				fontBytes: fontFiles.reduce((sum, entry) => sum + (entry.transferSize || entry.encodedBodySize || 0), 0),
+				preloadedFonts: document.querySelectorAll('link[rel="preload"][as="font"]').length
+			};
+		})()
+		`, &raw),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTypographyAudit(raw), nil
+}
+
+func buildTypographyAudit(raw rawTypography) *types.TypographyAudit {
+	audit := &types.TypographyAudit{
+		Scale:          []types.TypeScaleStep{},
+		Weights:        make(map[string]int),
+		Families:       make(map[string]int),
+		FontFaces:      raw.FontFaces,
+		WebFontFiles:   raw.FontFiles,
+		WebFontBytes:   raw.FontBytes,
+		PreloadedFonts: raw.PreloadedFonts,
+		Issues:         []types.TypographyIssue{},
+	}
+	if audit.FontFaces == nil {
+		audit.FontFaces = []types.FontFaceInfo{}
+	}
+
+	steps := make(map[float64]*types.TypeScaleStep)
+	bodyChars := make(map[float64]int)
+	var lineHeights, charsPerLine []float64
+	longest := 0.0
+	for _, el := range raw.Elements {
+		// Zero-size text, e.g. the label of an image-replaced logo link, has no place on a scale
+		size := math.Round(el.FontSize*2) / 2
+		if size <= 0 {
+			continue
+		}
+		step, ok := steps[size]
+		if !ok {
+			step = &types.TypeScaleStep{Size: size, Tags: []string{}}
+			steps[size] = step
+		}
+		step.Elements++
+		step.Characters += el.Chars
+		if !containsString(step.Tags, el.Tag) {
+			step.Tags = append(step.Tags, el.Tag)
+		}
+		audit.Weights[el.FontWeight] += el.Chars
+		audit.Families[el.Family] += el.Chars
+
+		if !el.Body {
+			continue
+		}
+		bodyChars[size] += el.Chars
+		audit.BodyText.Paragraphs++
+		lineHeights = append(lineHeights, el.LineHeight)
+		if el.LineHeight < minLineHeight {
+			audit.BodyText.TightLineHeight++
+		}
+		if el.CharsLine > 0 {
+			charsPerLine = append(charsPerLine, el.CharsLine)
+			switch {
+			case el.CharsLine > maxCharsPerLine:
+				audit.BodyText.LongLines++
+			case el.CharsLine < minCharsPerLine:
+				audit.BodyText.ShortLines++
+			}
+			if el.CharsLine > longest {
+				longest = el.CharsLine
+				audit.BodyText.LongestLineSelector = el.Selector
+			}
+		}
+	}
+	audit.BodyText.LineHeight = round2(median(lineHeights))
+	audit.BodyText.CharsPerLine = math.Round(median(charsPerLine))
+
+	for _, step := range steps {
+		audit.Scale = append(audit.Scale, *step)
+	}
+	sort.Slice(audit.Scale, func(i, j int) bool { return audit.Scale[i].Size < audit.Scale[j].Size })
+	audit.DistinctSizes = len(audit.Scale)
+	audit.DistinctWeights = len(audit.Weights)
+	audit.DistinctFamilies = len(audit.Families)
+
+	// The base size is the size most body text is set in, falling back to all text.
+	audit.BaseSize = heaviestSize(bodyChars)
+	if audit.BaseSize == 0 {
+		all := make(map[float64]int)
+		for _, step := range audit.Scale {
+			all[step.Size] = step.Characters
+		}
+		audit.BaseSize = heaviestSize(all)
+	}
+	fitModularScale(audit)
+
+	addTypographyIssues(audit)
+	return audit
+}
+
+// fitModularScale finds the largest classic ratio that places every size on its own step
+// within the tolerance, or else the ratio with the smallest error.
+func fitModularScale(audit *types.TypographyAudit) {
+	if audit.BaseSize == 0 || len(audit.Scale) < 2 {
+		return
+	}
+
+	bestErr := math.Inf(1)
+	for _, ratio := range modularRatios {
+		fitErr, distinct := scaleError(audit.Scale, audit.BaseSize, ratio.Ratio)
+		if distinct && fitErr <= scaleFitTolerance {
+			audit.ScaleRatio, audit.ScaleRatioName, audit.ScaleFitError = ratio.Ratio, ratio.Name, round2(fitErr)
+			audit.FollowsRatio = true
+			break
+		}
+		if fitErr < bestErr {
+			bestErr = fitErr
+			audit.ScaleRatio, audit.ScaleRatioName, audit.ScaleFitError = ratio.Ratio, ratio.Name, round2(fitErr)
+		}
+	}
+	// No ratio produced a finite error, so there are no steps to report
+	if audit.ScaleRatio == 0 {
+		return
+	}
+
+	for i := range audit.Scale {
+		step := nearestStep(audit.Scale[i].Size, audit.BaseSize, audit.ScaleRatio)
+		audit.Scale[i].Step = step
+		audit.Scale[i].Expected = round2(audit.BaseSize * math.Pow(audit.ScaleRatio, float64(step)))
+	}
+}
+
+// scaleError returns the character-weighted mean relative deviation of the sizes from the
+// nearest step of the ratio, and whether every size lands on a different step.
+func scaleError(scale []types.TypeScaleStep, base, ratio float64) (float64, bool) {
+	used := make(map[int]bool)
+	distinct := true
+	var sum, weight float64
+	for _, s := range scale {
+		step := nearestStep(s.Size, base, ratio)
+		if used[step] {
+			distinct = false
+		}
+		used[step] = true
+		expected := base * math.Pow(ratio, float64(step))
+		w := float64(s.Elements)
+		sum += math.Abs(s.Size-expected) / s.Size * w
+		weight += w
+	}
+	if weight == 0 {
+		return 0, distinct
+	}
+	return sum / weight, distinct
+}
+
+func nearestStep(size, base, ratio float64) int {
+	return int(math.Round(math.Log(size/base) / math.Log(ratio)))
+}
+
+func heaviestSize(chars map[float64]int) float64 {
+	size, best := 0.0, 0
+	for s, c := range chars {
+		if c > best || (c == best && s < size) {
+			size, best = s, c
+		}
+	}
+	return size
+}
+
+func addTypographyIssues(audit *types.TypographyAudit) {
+	add := func(kind, message, selector string) {
+		audit.Issues = append(audit.Issues, types.TypographyIssue{Type: kind, Message: message, Selector: selector})
+	}
+
+	if audit.DistinctSizes > maxFontSizes {
+		add("too-many-sizes", fmt.Sprintf("%d font sizes in use; a type scale rarely needs more than %d", audit.DistinctSizes, maxFontSizes), "")
+	}
+	if audit.DistinctWeights > maxFontWeights {
+		add("too-many-weights", fmt.Sprintf("%d font weights in use; keep it to %d or fewer", audit.DistinctWeights, maxFontWeights), "")
+	}
+	if audit.DistinctFamilies > maxFontFamilies {
+		add("too-many-families", fmt.Sprintf("%d font families in use; keep it to %d or fewer", audit.DistinctFamilies, maxFontFamilies), "")
+	}
+	if len(audit.Scale) >= 2 && !audit.FollowsRatio {
+		add("no-modular-scale", fmt.Sprintf("Font sizes do not follow a modular ratio (closest: %s, %.0f%% off)",
+			audit.ScaleRatioName, audit.ScaleFitError*100), "")
+	}
+	if audit.BaseSize > 0 && audit.BaseSize < 16 {
+		add("small-base-size", fmt.Sprintf("Body text is %.1fpx; 16px or more is easier to read", audit.BaseSize), "")
+	}
+	if audit.BodyText.TightLineHeight > 0 {
+		add("tight-line-height", fmt.Sprintf("%d paragraphs have a line-height below %.1f", audit.BodyText.TightLineHeight, minLineHeight), "")
+	}
+	if audit.BodyText.LongLines > 0 {
+		add("long-lines", fmt.Sprintf("%d paragraphs have more than %d characters per line", audit.BodyText.LongLines, maxCharsPerLine),
+			audit.BodyText.LongestLineSelector)
+	}
+	if audit.BodyText.ShortLines > 0 {
+		add("short-lines", fmt.Sprintf("%d paragraphs have fewer than %d characters per line", audit.BodyText.ShortLines, minCharsPerLine), "")
+	}
+
+	// Faces with font-display auto/block hide text for up to 3s while the file downloads.
+	var blocking []string
+	for _, face := range audit.FontFaces {
+		if face.Status == "loaded" && (face.Display == "auto" || face.Display == "block") {
+			name := face.Family + " " + face.Weight
+			if !containsString(blocking, name) {
+				blocking = append(blocking, name)
+			}
+		}
+	}
+	switch {
+	case len(blocking) == 0:
+		audit.FOITRisk = "low"
+	case audit.PreloadedFonts > 0:
+		audit.FOITRisk = "medium"
+	default:
+		audit.FOITRisk = "high"
+	}
+	if len(blocking) > 0 {
+		add("font-display", "Text may be invisible while these fonts load (font-display: auto/block): "+strings.Join(blocking, ", ")+
+			"; use font-display: swap or optional", "")
+	}
+	if audit.WebFontFiles > 6 {
+		add("many-font-files", strconv.Itoa(audit.WebFontFiles)+" web font files are downloaded; subset or drop unused weights", "")
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -7,51 +7,6 @@ import (
 	"github.com/chromedp/chromedp"
 )
 
-func AnalyzeColorUsage(ctx context.Context) (map[string]interface{}, error) {
-	fmt.Println("Analyzing color usage...")
-	var result map[string]interface{}
-	err := chromedp.Run(ctx,
-		chromedp.EvaluateAsDevTools(`
-		(function() {
-			const allElements = document.querySelectorAll('*'); // Select all elements on the page
-			const colorUsage = new Set(); // To store unique colors
-
-			allElements.forEach(el => {
-				const styles = window.getComputedStyle(el);
-
-				// Get color, background color, and border color
-				const color = styles.color;
-				const backgroundColor = styles.backgroundColor;
-				const borderColor = styles.borderColor;
-
-				// Add only non-transparent colors
-				if (color && color !== 'rgba(0, 0, 0, 0)' && color !== 'transparent') {
-					colorUsage.add(color);
-				}
-				if (backgroundColor && backgroundColor !== 'rgba(0, 0, 0, 0)' && backgroundColor !== 'transparent') {
-					colorUsage.add(backgroundColor);
-				}
-				if (borderColor && borderColor !== 'rgba(0, 0, 0, 0)' && borderColor !== 'transparent') {
-					colorUsage.add(borderColor);
-				}
-			});
-
-			const result = {
-				totalColors: colorUsage.size,
-				colors: Array.from(colorUsage) // Convert the Set to an Array
-			};
-
-			return result;
-		})();
-		`, &result),
-	)
-
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
-}
-
 func AnalyzeFontUsage(ctx context.Context) (map[string]interface{}, error) {
 	var result map[string]interface{}
 	fmt.Println("Analyzing font usage...")
@@ -135,7 +90,6 @@ func AnalyzeFontUsage(ctx context.Context) (map[string]interface{}, error) {
 	return result, nil
 }
 
-
 // Deprecated
 func AnalyzeFontSizes(ctx context.Context) (string, error) {
 	var result string
@@ -0,0 +1,142 @@
+package annotate
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+)
+
+// Severity colors, matching the badges in the HTML report.
+var severityColors = map[string]color.RGBA{
+	"critical": {220, 38, 38, 255},
+	"serious":  {234, 88, 12, 255},
+	"moderate": {245, 158, 11, 255},
+	"minor":    {37, 99, 235, 255},
+}
+
+// digitGlyphs is a 3x5 bitmap font for the badge numbers; each row uses the low three bits,
+// the highest of them being the left column.
+var digitGlyphs = [10][5]uint8{
+	{7, 5, 5, 5, 7}, // 0
+	{2, 6, 2, 2, 7}, // 1
+	{7, 1, 7, 4, 7}, // 2
+	{7, 1, 7, 1, 7}, // 3
+	{5, 5, 7, 1, 1}, // 4
+	{7, 4, 7, 1, 7}, // 5
+	{7, 4, 7, 5, 7}, // 6
+	{7, 1, 1, 1, 1}, // 7
+	{7, 5, 7, 5, 7}, // 8
+	{7, 5, 7, 1, 7}, // 9
+}
+
+// Capture takes a full-page PNG of the current layout and draws the markers on it. It
+// returns nil when there is nothing to mark.
+func Capture(ctx context.Context, name string, markers []types.AnnotationMarker) (*types.AnnotatedScreenshot, error) {
+	if len(markers) == 0 {
+		return nil, nil
+	}
+
+	shot, err := screenshot.Take(ctx, screenshot.Options{Mode: screenshot.ModeFullPage, Format: screenshot.FormatPNG, ThumbnailWidth: -1})
+	if err != nil || shot == nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(shot.Data)
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s screenshot: %w", name, err)
+	}
+
+	scale := float64(img.Bounds().Dx()) / shot.Clip.Width
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Draw(img, scale, markers)); err != nil {
+		return nil, err
+	}
+
+	return &types.AnnotatedScreenshot{
+		Name:    name,
+		Width:   shot.Clip.Width,
+		Height:  shot.Clip.Height,
+		Image:   base64.StdEncoding.EncodeToString(buf.Bytes()),
+		Markers: markers,
+	}, nil
+}
+
+// Draw copies the image and draws every marker on it as a box in its severity color with
+// the number in a badge at the top left corner. Scale converts the CSS pixels of the marker
+// rectangles into image pixels, e.g. 2 for a screenshot at a device pixel ratio of 2.
+func Draw(img image.Image, scale float64, markers []types.AnnotationMarker) *image.RGBA {
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+
+	stroke := max(2, int(scale*2))
+	pixel := max(2, int(scale*2)) // Size of one glyph pixel
+	for _, marker := range markers {
+		c, ok := severityColors[marker.Severity]
+		if !ok {
+			c = severityColors["minor"]
+		}
+		box := image.Rect(
+			int(marker.Rect.X*scale), int(marker.Rect.Y*scale),
+			int((marker.Rect.X+marker.Rect.Width)*scale), int((marker.Rect.Y+marker.Rect.Height)*scale),
+		).Intersect(out.Bounds())
+		if box.Empty() {
+			continue
+		}
+		frame(out, box, stroke, c)
+		badge(out, box.Min, strconv.Itoa(marker.Number), pixel, c)
+	}
+	return out
+}
+
+// frame draws the outline of a rectangle with the given stroke width inside its bounds.
+func frame(img *image.RGBA, r image.Rectangle, stroke int, c color.RGBA) {
+	fill := image.NewUniform(c)
+	stroke = min(stroke, r.Dx()/2+1, r.Dy()/2+1)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+stroke), fill, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Max.Y-stroke, r.Max.X, r.Max.Y), fill, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+stroke, r.Max.Y), fill, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Max.X-stroke, r.Min.Y, r.Max.X, r.Max.Y), fill, image.Point{}, draw.Src)
+}
+
+// badge draws the label in white on a filled box straddling the corner, moved inside the
+// image where the corner is at an edge.
+func badge(img *image.RGBA, corner image.Point, label string, pixel int, c color.RGBA) {
+	padding := pixel * 2
+	width := len(label)*4*pixel - pixel + 2*padding
+	height := 5*pixel + 2*padding
+
+	origin := corner.Sub(image.Pt(width/2, height/2))
+	origin.X = max(img.Bounds().Min.X, min(origin.X, img.Bounds().Max.X-width))
+	origin.Y = max(img.Bounds().Min.Y, min(origin.Y, img.Bounds().Max.Y-height))
+
+	draw.Draw(img, image.Rect(origin.X, origin.Y, origin.X+width, origin.Y+height), image.NewUniform(c), image.Point{}, draw.Src)
+
+	white := image.NewUniform(color.RGBA{255, 255, 255, 255})
+	x := origin.X + padding
+	for _, digit := range label {
+		glyph := digitGlyphs[digit-'0']
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+				px, py := x+col*pixel, origin.Y+padding+row*pixel
+				draw.Draw(img, image.Rect(px, py, px+pixel, py+pixel), white, image.Point{}, draw.Src)
+			}
+		}
+		x += 4 * pixel
+	}
+}
@@ -0,0 +1,136 @@
+package annotate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"uxlyze/analyzer/pkg/types"
+)
+
+// maxMarkers caps the boxes drawn per screenshot; the most severe issues are kept.
+const maxMarkers = 40
+
+// severityRank orders severities from most to least severe, using the impact levels of the
+// accessibility audit.
+var severityRank = map[string]int{
+	"critical": 0,
+	"serious":  1,
+	"moderate": 2,
+	"minor":    3,
+}
+
+// DesktopMarkers collects the flagged elements of the desktop layout: low contrast text,
+// problem images and accessibility violations. Issues on the same element share one marker.
+func DesktopMarkers(report *types.Report) []types.AnnotationMarker {
+	var c collector
+	if report.Contrast != nil {
+		for _, issue := range report.Contrast.Issues {
+			severity, level, required := "minor", "AAA", issue.RequiredAAA
+			if !issue.PassesAA {
+				severity, level, required = "serious", "AA", issue.RequiredAA
+			}
+			c.add(issue.Selector, issue.Rect, severity, "contrast",
+				fmt.Sprintf("Contrast %.2f:1 is below %.1f:1 (%s)", issue.Ratio, required, level))
+		}
+	}
+	if report.Images != nil {
+		for _, image := range report.Images.Images {
+			if len(image.Issues) == 0 {
+				continue
+			}
+			severity := "minor"
+			if !image.HasAlt && !image.Decorative {
+				severity = "serious"
+			}
+			c.add(image.Selector, image.Rect, severity, "images", strings.Join(image.Issues, "; "))
+		}
+	}
+	if report.Accessibility != nil {
+		for _, violation := range report.Accessibility.Violations {
+			c.add(violation.Selector, violation.Rect, violation.Impact, "accessibility", violation.Message)
+		}
+	}
+	return c.markers()
+}
+
+// MobileMarkers collects the flagged elements of the mobile layout: tap targets that are too
+// small or too close together.
+func MobileMarkers(report *types.Report) []types.AnnotationMarker {
+	var c collector
+	if report.TapTargets != nil {
+		for _, target := range report.TapTargets.Offenders {
+			c.add(target.Selector, target.Rect, "moderate", "tapTargets", strings.Join(target.Issues, "; "))
+		}
+	}
+	return c.markers()
+}
+
+// collector merges issues by element.
+type collector struct {
+	list  []*types.AnnotationMarker
+	index map[string]*types.AnnotationMarker
+}
+
+func (c *collector) add(selector string, rect types.Rect, severity, source, issue string) {
+	if rect.Width < 1 || rect.Height < 1 || issue == "" {
+		return
+	}
+	if _, ok := severityRank[severity]; !ok {
+		severity = "minor"
+	}
+	if c.index == nil {
+		c.index = make(map[string]*types.AnnotationMarker)
+	}
+
+	key := selector
+	if key == "" {
+		key = fmt.Sprintf("%.0f,%.0f,%.0f,%.0f", rect.X, rect.Y, rect.Width, rect.Height)
+	}
+	marker, ok := c.index[key]
+	if !ok {
+		marker = &types.AnnotationMarker{Severity: severity, Selector: selector, Rect: rect, Sources: []string{}, Issues: []string{}}
+		c.index[key] = marker
+		c.list = append(c.list, marker)
+	}
+	if severityRank[severity] < severityRank[marker.Severity] {
+		marker.Severity = severity
+	}
+	if !contains(marker.Sources, source) {
+		marker.Sources = append(marker.Sources, source)
+	}
+	if !contains(marker.Issues, issue) {
+		marker.Issues = append(marker.Issues, issue)
+	}
+}
+
+// markers keeps the most severe markers and numbers them in reading order.
+func (c *collector) markers() []types.AnnotationMarker {
+	list := c.list
+	sort.SliceStable(list, func(i, j int) bool { return severityRank[list[i].Severity] < severityRank[list[j].Severity] })
+	if len(list) > maxMarkers {
+		list = list[:maxMarkers]
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Rect.Y != list[j].Rect.Y {
+			return list[i].Rect.Y < list[j].Rect.Y
+		}
+		return list[i].Rect.X < list[j].Rect.X
+	})
+
+	markers := make([]types.AnnotationMarker, len(list))
+	for i, marker := range list {
+		marker.Number = i + 1
+		markers[i] = *marker
+	}
+	return markers
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
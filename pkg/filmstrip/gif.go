@@ -0,0 +1,88 @@
+package filmstrip
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+
+	"uxlyze/analyzer/pkg/types"
+)
+
+// GIF settings.
+const (
+	gifWidth     = 480 // Largest frame width in pixels
+	gifFinalHold = 200 // Centiseconds the last frame stays on screen before looping
+	gifMinDelay  = 2   // Shortest frame delay most viewers honor, in centiseconds
+)
+
+// encodeGIF renders the frames as an animated GIF played at recording speed and returns it
+// in base64. Frames are scaled down to gifWidth and dithered to the Plan 9 palette.
+func encodeGIF(frames []types.FilmstripFrame) (string, error) {
+	if len(frames) == 0 {
+		return "", nil
+	}
+
+	anim := &gif.GIF{}
+	for i, f := range frames {
+		raw, err := base64.StdEncoding.DecodeString(f.Image)
+		if err != nil {
+			return "", err
+		}
+		img, err := jpeg.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return "", fmt.Errorf("decoding filmstrip frame at %.0fms: %w", f.Time, err)
+		}
+		img = downscale(img, gifWidth)
+
+		paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, img.Bounds().Min)
+
+		delay := gifFinalHold
+		if i+1 < len(frames) {
+			delay = max(gifMinDelay, int((frames[i+1].Time-f.Time)/10))
+		}
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// downscale shrinks the image to the given width, averaging the source pixels each target
+// pixel covers. Narrower images are returned unchanged.
+func downscale(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width {
+		return img
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, _ := img.At(sx, sy).RGBA()
+					r, g, b, n = r+cr>>8, g+cg>>8, b+cb>>8, n+1
+				}
+			}
+			i := out.PixOffset(x, y)
+			out.Pix[i], out.Pix[i+1], out.Pix[i+2], out.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), 255
+		}
+	}
+	return out
+}
@@ -0,0 +1,229 @@
+package filmstrip
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// Export selects what the filmstrip is exported as besides the selected frames.
+type Export string
+
+const (
+	ExportNone   Export = ""       // Only the selected frames
+	ExportGIF    Export = "gif"    // An animated GIF of every recorded frame
+	ExportFrames Export = "frames" // Every recorded frame with its timestamp
+)
+
+// Recording settings.
+const (
+	frameInterval  = 500 * time.Millisecond // Spacing of the regular filmstrip frames
+	maxFrames      = 24                     // Cap on the regular frames in the filmstrip
+	frameSlack     = 50                     // ms a frame may lag behind the timestamp it stands for
+	settleDelay    = time.Second            // Keep recording after load to catch late paints
+	screencastSize = 960                    // Largest frame width and height in pixels
+	frameQuality   = 70                     // JPEG quality of the frames
+)
+
+// Validate reports unknown export formats.
+func (e Export) Validate() error {
+	switch e {
+	case ExportNone, ExportGIF, ExportFrames:
+		return nil
+	default:
+		return fmt.Errorf("unknown filmstrip export %q", e)
+	}
+}
+
+type rawTimings struct {
+	TimeOrigin             float64 `json:"timeOrigin"`
+	FirstPaint             float64 `json:"firstPaint"`
+	FirstContentfulPaint   float64 `json:"firstContentfulPaint"`
+	LargestContentfulPaint float64 `json:"largestContentfulPaint"`
+	Load                   float64 `json:"load"`
+}
+
+// timingsJS reads the paint timings of the navigation. LCP is only exposed through a
+// PerformanceObserver, whose buffered entries arrive asynchronously.
+const timingsJS = `
+	(async function() {
+		const paint = {};
+		performance.getEntriesByType('paint').forEach(entry => paint[entry.name] = entry.startTime);
+		const nav = performance.getEntriesByType('navigation')[0];
+		const lcp = await new Promise(resolve => {
+			let last = 0;
+			try {
+				new PerformanceObserver(list => {
+					const entries = list.getEntries();
+					last = entries[entries.length - 1].startTime;
+				}).observe({type: 'largest-contentful-paint', buffered: true});
+			} catch (e) {}
+			setTimeout(() => resolve(last), 100);
+		});
+		return {
+			timeOrigin: performance.timeOrigin,
+			firstPaint: paint['first-paint'] || 0,
+			firstContentfulPaint: paint['first-contentful-paint'] || 0,
+			largestContentfulPaint: lcp,
+			load: nav ? (nav.loadEventEnd || nav.loadEventStart) : 0
+		};
+	})()
+`
+
+// frame is a screencast frame with its swap time in ms since the epoch.
+type frame struct {
+	epochMs float64
+	data    string
+}
+
+// Record navigates to the URL while recording a screencast and builds the filmstrip from
+// the frames: one every frameInterval until load, plus the frames shown at first paint,
+// first contentful paint, largest contentful paint and load.
+func Record(ctx context.Context, url string, export Export) (*types.Filmstrip, error) {
+	fmt.Println("Recording page-load filmstrip...")
+
+	var mu sync.Mutex
+	var frames []frame
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chromedp.ListenTarget(listenCtx, func(ev interface{}) {
+		e, ok := ev.(*page.EventScreencastFrame)
+		if !ok {
+			return
+		}
+		if e.Metadata != nil && e.Metadata.Timestamp != nil {
+			mu.Lock()
+			frames = append(frames, frame{float64(e.Metadata.Timestamp.Time().UnixNano()) / 1e6, e.Data})
+			mu.Unlock()
+		}
+		// Chrome sends the next frame only after the previous one was acknowledged
+		go chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			return page.ScreencastFrameAck(e.SessionID).Do(ctx)
+		}))
+	})
+
+	err := chromedp.Run(ctx,
+		page.StartScreencast().
+			WithFormat(page.ScreencastFormatJpeg).
+			WithQuality(frameQuality).
+			WithMaxWidth(screencastSize).
+			WithMaxHeight(screencastSize),
+		chromedp.Navigate(url),
+		chromedp.Sleep(settleDelay),
+		page.StopScreencast(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var timings rawTimings
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(timingsJS, &timings, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	})); err != nil {
+		return nil, err
+	}
+
+	cancel()
+	mu.Lock()
+	defer mu.Unlock()
+	strip := build(frames, timings)
+	switch export {
+	case ExportFrames:
+		strip.Sequence = sequence(frames, timings.TimeOrigin)
+	case ExportGIF:
+		strip.GIF, err = encodeGIF(sequence(frames, timings.TimeOrigin))
+		if err != nil {
+			return strip, err
+		}
+	}
+	return strip, nil
+}
+
+// build picks the filmstrip frames. A timestamp is represented by the last frame swapped at
+// or shortly after it, i.e. what the user saw at that moment.
+func build(frames []frame, timings rawTimings) *types.Filmstrip {
+	strip := &types.Filmstrip{
+		FirstPaint:             round1(timings.FirstPaint),
+		FirstContentfulPaint:   round1(timings.FirstContentfulPaint),
+		LargestContentfulPaint: round1(timings.LargestContentfulPaint),
+		Load:                   round1(timings.Load),
+		Recorded:               len(frames),
+		Frames:                 []types.FilmstripFrame{},
+	}
+	seq := sequence(frames, timings.TimeOrigin)
+	if len(seq) == 0 {
+		return strip
+	}
+
+	picked := make(map[int][]string)
+	shownAt := func(t float64) int {
+		index := 0
+		for i, f := range seq {
+			if f.Time <= t+frameSlack {
+				index = i
+			}
+		}
+		return index
+	}
+
+	end := math.Max(timings.Load, seq[len(seq)-1].Time)
+	step := float64(frameInterval.Milliseconds())
+	for i, t := 0, 0.0; t <= end && i < maxFrames; i, t = i+1, t+step {
+		index := shownAt(t)
+		if _, ok := picked[index]; !ok {
+			picked[index] = []string{}
+		}
+	}
+	for _, key := range []struct {
+		label string
+		time  float64
+	}{
+		{"First Paint", timings.FirstPaint},
+		{"First Contentful Paint", timings.FirstContentfulPaint},
+		{"Largest Contentful Paint", timings.LargestContentfulPaint},
+		{"Load", timings.Load},
+	} {
+		if key.time > 0 {
+			index := shownAt(key.time)
+			picked[index] = append(picked[index], key.label)
+		}
+	}
+
+	indexes := make([]int, 0, len(picked))
+	for index := range picked {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		f := seq[index]
+		f.Labels = picked[index]
+		strip.Frames = append(strip.Frames, f)
+	}
+	return strip
+}
+
+// sequence converts the frames swapped after navigation start to filmstrip frames in time
+// order; earlier frames still show the previous page.
+func sequence(frames []frame, timeOrigin float64) []types.FilmstripFrame {
+	seq := []types.FilmstripFrame{}
+	for _, f := range frames {
+		if t := f.epochMs - timeOrigin; t >= 0 {
+			seq = append(seq, types.FilmstripFrame{Time: round1(t), Labels: []string{}, Image: f.data})
+		}
+	}
+	sort.SliceStable(seq, func(i, j int) bool { return seq[i].Time < seq[j].Time })
+	return seq
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -1,23 +1,31 @@
 package process_worker
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/url"
 	"os"
+	"time"
 	"uxlyze/analyzer/pkg/report"
+	"uxlyze/analyzer/pkg/storage"
 	"uxlyze/analyzer/pkg/types"
+	"uxlyze/analyzer/pkg/visualdiff"
 
 	"github.com/lib/pq"
 )
 
 type DbReportConfig struct {
-	IncludePSI        bool `json:"includePSI"`
-	SkipUrlFetch      bool `json:"skipUrlFetch"`
-	IncludePreview    bool `json:"includePreview"`
-	IncludeAIAnalysis bool `json:"includeAIAnalysis"`
+	SkipUrlFetch bool `json:"skipUrlFetch"`
+
+	types.ReportConfig
+
+	// Visual regression against an earlier report of the same page
+	BaselineReportID string                  `json:"baselineReportId"`
+	DiffThreshold    float64                 `json:"diffThreshold"`
+	IgnoreRegions    map[string][]types.Rect `json:"ignoreRegions"` // CSS pixels, per screenshot name
 }
 
 // Report represents the structure of the data you are fetching
@@ -101,22 +109,84 @@ func AnalyzeReportWorker(id string) {
 		return
 	}
 
+	options, err := report.OptionsFromConfig(dbReport.ReportConfig.ReportConfig)
+	if err != nil {
+		log.Printf("Invalid report options for report ID %s, using defaults for them: %v\n", id, err)
+	}
+
 	// start the analysis
-	reportResult, err := report.Generate(
-		dbReport.WebURL,
-		dbReport.ReportConfig.IncludePreview,
-		dbReport.ReportConfig.IncludePSI,
-		dbReport.ReportConfig.IncludeAIAnalysis,
-	)
+	reportResult, err := report.Generate(dbReport.WebURL, options)
 
 	if err != nil {
 		log.Printf("Error generating report for report ID %s: %v\n", id, err)
 		return
 	}
 
+	// Screenshots go to the artifact store, when one is configured, instead of into the row
+	store, err := storage.NewFromEnv()
+	if err != nil {
+		log.Printf("Error configuring artifact store, keeping screenshots inline: %v\n", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if baselineID := dbReport.ReportConfig.BaselineReportID; baselineID != "" {
+		baseline, err := fetchReportResult(baselineID, db)
+		if err == nil && store != nil {
+			err = storage.LoadReportImages(ctx, store, baseline)
+		}
+		if err != nil {
+			log.Printf("Error fetching baseline report %s for report ID %s: %v\n", baselineID, id, err)
+		} else {
+			reportResult.VisualRegression = visualdiff.CompareReports(baseline, reportResult, visualdiff.ReportOptions{
+				Threshold:     dbReport.ReportConfig.DiffThreshold,
+				IgnoreRegions: dbReport.ReportConfig.IgnoreRegions,
+			})
+			reportResult.VisualRegression.Baseline = baselineID
+		}
+	}
+
+	if store != nil {
+		if err := storage.StoreReportImages(ctx, store, reportResult); err != nil {
+			log.Printf("Error storing screenshots for report ID %s: %v\n", id, err)
+		}
+	}
+
 	storeReportResult(reportResult, &dbReport, db)
 }
 
+// baselineResult holds the parts of a stored report the visual diff needs. Older results
+// do not unmarshal into types.Report (MobileFriendly used to be a bool, Readability a
+// string), so only these fields are read.
+type baselineResult struct {
+	URL          string
+	Screenshots  map[string]string
+	AboveTheFold []struct {
+		Device     string `json:"device"`
+		Screenshot string `json:"screenshot"`
+	} `json:"aboveTheFold"`
+}
+
+// fetchReportResult loads the screenshots of an earlier report.
+func fetchReportResult(reportID string, db *sql.DB) (*types.Report, error) {
+	var resultBytes []byte
+	query := `SELECT result FROM report_results WHERE report_id = $1 LIMIT 1`
+	if err := db.QueryRow(query, reportID).Scan(&resultBytes); err != nil {
+		return nil, err
+	}
+
+	var result baselineResult
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		return nil, err
+	}
+
+	report := &types.Report{URL: result.URL, Screenshots: result.Screenshots}
+	for _, fold := range result.AboveTheFold {
+		report.AboveTheFold = append(report.AboveTheFold, &types.FoldAnalysis{Device: fold.Device, Screenshot: fold.Screenshot})
+	}
+	return report, nil
+}
+
 func storeReportResult(reportResult *types.Report, dbReport *DbReport, db *sql.DB) {
 	// Serialize reportResult to JSON
 	reportJSON, err := json.Marshal(reportResult)
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"uxlyze/analyzer/pkg/analysis"
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+	"uxlyze/analyzer/pkg/visualdiff"
+
+	"github.com/chromedp/chromedp"
+)
+
+// CompareWithDesign loads the URL and compares it with a reference design export, so QA can
+// catch where the implementation drifts from the mock.
+//
+// Parameters:
+//
+//	url - The URL of the implemented page.
+//	reference - The design export as a base64 PNG or JPEG, optionally as a data URI.
+//	device - The viewport the design was made for. Its pixel ratio is derived from the
+//	  width of the reference; a zero width uses the width of the reference.
+//	opts - Per-pixel threshold and regions to ignore, in reference image pixels.
+func CompareWithDesign(url string, reference string, device screenshot.Device, opts visualdiff.Options) (*types.DesignComparison, error) {
+	log.Println("Starting design comparison for", url)
+	startTime := time.Now()
+
+	ref, err := visualdiff.DecodeBase64Image(reference)
+	if err != nil {
+		return nil, fmt.Errorf("decoding reference design: %w", err)
+	}
+
+	ctx, cancel := chromedp.NewContext(context.Background())
+	defer cancel()
+
+	ctx, cancel = context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	// Emulate the design viewport before loading so the page lays out for it from the start
+	device, err = analysis.DesignDevice(ref, device)
+	if err != nil {
+		return nil, err
+	}
+	if err := screenshot.Emulate(ctx, device); err != nil {
+		return nil, err
+	}
+	if err := chromedp.Run(ctx, chromedp.Navigate(url)); err != nil {
+		return nil, err
+	}
+
+	comparison, err := analysis.CompareWithDesign(ctx, ref, device, opts)
+	if err != nil {
+		if comparison == nil {
+			return nil, err
+		}
+		log.Printf("Error labelling design regions: %v\n", err)
+	}
+	comparison.URL = url
+
+	log.Printf("Design comparison took: %v\n", time.Since(startTime))
+	return comparison, nil
+}
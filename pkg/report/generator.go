@@ -11,6 +11,8 @@ import (
 
 	"uxlyze/analyzer/pkg/ai"
 	"uxlyze/analyzer/pkg/analysis"
+	"uxlyze/analyzer/pkg/annotate"
+	"uxlyze/analyzer/pkg/filmstrip"
 	"uxlyze/analyzer/pkg/screenshot"
 	"uxlyze/analyzer/pkg/types"
 
@@ -53,12 +55,15 @@ func SaveBase64ToLocal(base64String string, pathName string) {
 // Parameters:
 //
 //	url - The URL of the website to generate the report for.
+//	opts - The optional steps to run and the screenshot settings. SubmitEmptyForms is only
+//	  honored when the server sets ALLOW_FORM_SUBMISSION, and only for local fixtures
+//	  (file:// URLs and loopback hosts).
 //
 // Returns:
 //
 //	*types.Report - A pointer to the generated report containing the analysis results.
 //	error - An error if any step of the report generation fails.
-func Generate(url string, takeScreenshots bool, includePSI bool, includeAIAnalysis bool) (*types.Report, error) {
+func Generate(url string, opts Options) (*types.Report, error) {
 	log.Println("Starting report generation for", url)
 	startTime := time.Now()
 
@@ -66,38 +71,56 @@ func Generate(url string, takeScreenshots bool, includePSI bool, includeAIAnalys
 	ctx, cancel := chromedp.NewContext(context.Background())
 	defer cancel()
 
-	// Set a timeout for all chromedp actions.
-	ctx, cancel = context.WithTimeout(ctx, 120*time.Second)
-	defer cancel()
-
-	// Start timer for navigation.
-	stepStart := time.Now()
-	err := chromedp.Run(ctx, chromedp.Navigate(url))
-	if err != nil {
-		return nil, err
+	// Set a timeout for all chromedp actions. Auto-scrolling brings its own budget.
+	timeout := 120 * time.Second
+	if opts.AutoScroll {
+		timeout += analysis.AutoScrollBudget + analysis.AutoRescrollBudget
 	}
-	log.Printf("Navigation to URL took: %v\n", time.Since(stepStart))
+	ctx, cancel = context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	// Initialize the report.
 	var report types.Report
 	report.URL = url
 	report.Screenshots = make(map[string]string)
+	report.Thumbnails = make(map[string]string)
+	report.ScreenshotClips = make(map[string]types.Rect)
+
+	// Start timer for navigation.
+	stepStart := time.Now()
+	var err error
+	if opts.RecordFilmstrip {
+		// The filmstrip is recorded during the navigation itself
+		report.Filmstrip, err = filmstrip.Record(ctx, url, opts.FilmstripExport)
+		if err != nil {
+			log.Printf("Error recording filmstrip: %v\n", err)
+		}
+	}
+	if report.Filmstrip == nil {
+		err = chromedp.Run(ctx, chromedp.Navigate(url))
+		if err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("Navigation to URL took: %v\n", time.Since(stepStart))
 
-	// // Step: Analyze Visual Hierarchy
-	// stepStart = time.Now()
-	// report.FontSizes, err = analysis.AnalyzeFontSizes(ctx)
-	// if err != nil {
-	// 	log.Printf("Error analyzing visual hierarchy: %v\n", err)
-	// }
-	// log.Printf("Analyzing visual hierarchy took: %v\n", time.Since(stepStart))
+	// Step: Auto-scroll to load lazy content
+	if opts.AutoScroll {
+		stepStart = time.Now()
+		report.AutoScroll, err = analysis.AutoScroll(ctx, analysis.AutoScrollBudget)
+		if err != nil {
+			log.Printf("Error auto-scrolling: %v\n", err)
+		}
+		log.Printf("Auto-scrolling took: %v\n", time.Since(stepStart))
+	}
 
-	// Step: Analyze Mobile Friendliness
+	// Step: Analyze Heading Outline
 	stepStart = time.Now()
-	report.MobileFriendly, err = analysis.AnalyzeMobileFriendly(ctx)
+	report.Headings, err = analysis.AnalyzeHeadings(ctx)
 	if err != nil {
-		log.Printf("Error analyzing mobile friendliness: %v\n", err)
+		log.Printf("Error analyzing heading outline: %v\n", err)
 	}
-	log.Printf("Analyzing mobile friendliness took: %v\n", time.Since(stepStart))
+	log.Printf("Analyzing heading outline took: %v\n", time.Since(stepStart))
 
 	// Step: Analyze Readability
 	stepStart = time.Now()
@@ -131,6 +154,62 @@ func Generate(url string, takeScreenshots bool, includePSI bool, includeAIAnalys
 	}
 	log.Printf("Analyzing font usage took: %v\n", time.Since(stepStart))
 
+	// Step: Analyze Typography
+	stepStart = time.Now()
+	report.Typography, err = analysis.AnalyzeTypography(ctx)
+	if err != nil {
+		log.Printf("Error analyzing typography: %v\n", err)
+	}
+	log.Printf("Analyzing typography took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Spacing
+	stepStart = time.Now()
+	report.Spacing, err = analysis.AnalyzeSpacing(ctx)
+	if err != nil {
+		log.Printf("Error analyzing spacing: %v\n", err)
+	}
+	log.Printf("Analyzing spacing took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Design Tokens
+	stepStart = time.Now()
+	report.DesignTokens, err = analysis.AnalyzeDesignTokens(ctx, report.ColorUsage, report.Typography, report.Spacing)
+	if err != nil {
+		log.Printf("Error analyzing design tokens: %v\n", err)
+	}
+	log.Printf("Analyzing design tokens took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Sections
+	stepStart = time.Now()
+	report.Sections, err = analysis.AnalyzeSections(ctx, opts.TakeScreenshots)
+	if err != nil {
+		log.Printf("Error analyzing sections: %v\n", err)
+	}
+	log.Printf("Analyzing sections took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze CTAs
+	stepStart = time.Now()
+	report.CTA, err = analysis.AnalyzeCTAs(ctx)
+	if err != nil {
+		log.Printf("Error analyzing CTAs: %v\n", err)
+	}
+	log.Printf("Analyzing CTAs took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Forms
+	stepStart = time.Now()
+	report.Forms, err = analysis.AnalyzeForms(ctx, url, opts.SubmitEmptyForms)
+	if err != nil {
+		log.Printf("Error analyzing forms: %v\n", err)
+	}
+	log.Printf("Analyzing forms took: %v\n", time.Since(stepStart))
+
+	// Safe mode reloads the page, which drops the content the auto-scroll loaded. The content
+	// is cached by now, so a shorter pass reloads it.
+	if opts.AutoScroll && report.Forms != nil && report.Forms.SafeMode {
+		if _, err := analysis.AutoScroll(ctx, analysis.AutoRescrollBudget); err != nil {
+			log.Printf("Error auto-scrolling after form submission: %v\n", err)
+		}
+	}
+
 	// Step: Analyze SEO
 	stepStart = time.Now()
 	report.SEO, err = analysis.AnalyzeSEO(ctx)
@@ -139,56 +218,152 @@ func Generate(url string, takeScreenshots bool, includePSI bool, includeAIAnalys
 	}
 	log.Printf("Analyzing SEO took: %v\n", time.Since(stepStart))
 
+	// Step: Analyze Structured Data
+	stepStart = time.Now()
+	report.StructuredData, err = analysis.AnalyzeStructuredData(ctx)
+	if err != nil {
+		log.Printf("Error analyzing structured data: %v\n", err)
+	}
+	log.Printf("Analyzing structured data took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Images
+	stepStart = time.Now()
+	report.Images, err = analysis.AnalyzeImages(ctx)
+	if err != nil {
+		log.Printf("Error analyzing images: %v\n", err)
+	}
+	log.Printf("Analyzing images took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Accessibility
+	stepStart = time.Now()
+	report.Accessibility, err = analysis.AnalyzeAccessibility(ctx)
+	if err != nil {
+		log.Printf("Error analyzing accessibility: %v\n", err)
+	}
+	log.Printf("Analyzing accessibility took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Color Contrast
+	stepStart = time.Now()
+	report.Contrast, err = analysis.AnalyzeContrast(ctx)
+	if err != nil {
+		log.Printf("Error analyzing color contrast: %v\n", err)
+	}
+	log.Printf("Analyzing color contrast took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Keyboard Navigation
+	stepStart = time.Now()
+	report.Keyboard, err = analysis.AnalyzeKeyboardNavigation(ctx)
+	if err != nil {
+		log.Printf("Error analyzing keyboard navigation: %v\n", err)
+	}
+	log.Printf("Analyzing keyboard navigation took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Mobile Profile
+	stepStart = time.Now()
+	err = screenshot.Emulate(ctx, screenshot.MobileDevice)
+	if err != nil {
+		log.Printf("Error emulating mobile device: %v\n", err)
+	} else {
+		report.TapTargets, err = analysis.AnalyzeTapTargets(ctx, screenshot.MobileDevice.Name)
+		if err != nil {
+			log.Printf("Error analyzing tap targets: %v\n", err)
+		}
+
+		report.MobileFriendly, err = analysis.AnalyzeMobileFriendly(ctx)
+		if err != nil {
+			log.Printf("Error analyzing mobile friendliness: %v\n", err)
+		}
+
+		_ = screenshot.ResetEmulation(ctx)
+	}
+	log.Printf("Analyzing mobile profile took: %v\n", time.Since(stepStart))
+
+	// Step: Analyze Above the Fold
+	stepStart = time.Now()
+	foldDevices := []screenshot.Device{screenshot.DesktopDevice, screenshot.TabletDevice, screenshot.MobileDevice}
+	report.AboveTheFold, err = analysis.AnalyzeAboveTheFold(ctx, foldDevices, opts.TakeScreenshots)
+	if err != nil {
+		log.Printf("Error analyzing above-the-fold content: %v\n", err)
+	}
+	log.Printf("Analyzing above-the-fold content took: %v\n", time.Since(stepStart))
+
 	// Step: Capture Screenshots
-	if takeScreenshots {
+	if opts.TakeScreenshots {
 
 		stepStart = time.Now()
-		report.Screenshots["Desktop"], err = screenshot.Capture(ctx, "body")
+		err = takeScreenshot(ctx, &report, "Desktop", opts.Screenshot)
 		if err != nil {
 			log.Printf("Error capturing Desktop screenshot: %v\n", err)
 		}
 		log.Printf("Capturing Desktop screenshot took: %v\n", time.Since(stepStart))
 
 		stepStart = time.Now()
-		report.Screenshots["Navigation"], err = screenshot.Capture(ctx, "nav")
+		err = annotateScreenshot(ctx, &report, "Desktop", annotate.DesktopMarkers(&report))
+		if err != nil {
+			log.Printf("Error annotating Desktop screenshot: %v\n", err)
+		}
+		log.Printf("Annotating Desktop screenshot took: %v\n", time.Since(stepStart))
+
+		stepStart = time.Now()
+		navigationOptions := opts.Screenshot
+		navigationOptions.Mode, navigationOptions.Selector = screenshot.ModeElement, "nav"
+		err = takeScreenshot(ctx, &report, "Navigation", navigationOptions)
 		if err != nil {
 			log.Printf("Error capturing navigation screenshot: %v\n", err)
 		}
 		log.Printf("Capturing Navigation screenshot took: %v\n", time.Since(stepStart))
 
+		if report.Keyboard != nil {
+			stepStart = time.Now()
+			report.Screenshots["FocusOrder"], err = analysis.CaptureFocusOrder(ctx, report.Keyboard)
+			if err != nil {
+				log.Printf("Error capturing focus order screenshot: %v\n", err)
+			}
+			log.Printf("Capturing FocusOrder screenshot took: %v\n", time.Since(stepStart))
+		}
+
 		// Emulate mobile view and capture mobile friendliness screenshot.
 		stepStart = time.Now()
-		// calculate the page height
-		var pageHeight int64
-		err = chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`document.body.scrollHeight`, &pageHeight))
-		if err != nil {
-			log.Printf("Error calculating page height: %v\n", err)
+		err = screenshot.Emulate(ctx, screenshot.MobileDevice)
+		if err == nil {
+			err = takeScreenshot(ctx, &report, "Mobile", opts.Screenshot)
 		}
-		_ = chromedp.Run(ctx, chromedp.EmulateViewport(350, pageHeight, chromedp.EmulateScale(2.0)))
-		report.Screenshots["Mobile"], err = screenshot.Capture(ctx, "body")
 		if err != nil {
 			log.Printf("Error capturing mobile friendliness screenshot: %v\n", err)
 		}
 		log.Printf("Capturing Mobile screenshot took: %v\n", time.Since(stepStart))
 
+		// Tap targets were measured in the same mobile profile, so their boxes line up
+		stepStart = time.Now()
+		err = annotateScreenshot(ctx, &report, "Mobile", annotate.MobileMarkers(&report))
+		if err != nil {
+			log.Printf("Error annotating Mobile screenshot: %v\n", err)
+		}
+		log.Printf("Annotating Mobile screenshot took: %v\n", time.Since(stepStart))
+
 		// Reset to default desktop viewport.
-		_ = chromedp.Run(ctx, chromedp.EmulateViewport(0, 0))
+		_ = screenshot.ResetEmulation(ctx)
 
 	}
 	// Perform Gemini UX analysis
-	if includeAIAnalysis {
+	if opts.IncludeAIAnalysis {
 
 		if report.Screenshots["Desktop"] == "" {
 			log.Println("No screenshot available for Gemini analysis")
 			//  take the screenshot
-			report.Screenshots["Desktop"], err = screenshot.Capture(ctx, "body")
+			err = takeScreenshot(ctx, &report, "Desktop", opts.Screenshot)
 			if err != nil {
 				log.Printf("Error capturing Desktop screenshot: %v\n", err)
 			}
 		}
+		// The extension tells Gemini the image type
+		format := opts.Screenshot.Format
+		if format == "" {
+			format = screenshot.FormatPNG
+		}
 		tempUuid := uuid.New()
-		tempImagePath := "temp_screenshot_" + tempUuid.String() + ".png"
-		SaveBase64ToLocal("data:image/png;base64,"+report.Screenshots["Desktop"], tempImagePath)
+		tempImagePath := "temp_screenshot_" + tempUuid.String() + "." + string(format)
+		SaveBase64ToLocal("data:image/"+string(format)+";base64,"+report.Screenshots["Desktop"], tempImagePath)
 		if err != nil {
 			log.Printf("Error saving temporary screenshot: %v\n", err)
 		} else {
@@ -197,19 +372,23 @@ func Generate(url string, takeScreenshots bool, includePSI bool, includeAIAnalys
 				log.Printf("Error analyzing UX with Gemini: %v\n", err)
 			} else {
 				report.GeminiAnalysis = geminiAnalysis
+				if report.ColorUsage != nil {
+					report.ColorUsage.Gemini = analysis.ComparePaletteWithGemini(report.ColorUsage, geminiAnalysis.ColorScheme)
+				}
 			}
 			os.Remove(tempImagePath)
 		}
 
 	}
 
-	if !takeScreenshots {
+	if !opts.TakeScreenshots {
 		report.Screenshots["Desktop"] = ""
 		report.Screenshots["Mobile"] = ""
 		report.Screenshots["Navigation"] = ""
+		delete(report.Thumbnails, "Desktop")
 	}
 
-	if includePSI {
+	if opts.IncludePSI {
 		stepStart = time.Now()
 		psi, err := GetPageSpeedInsights(url)
 		if err != nil {
@@ -228,3 +407,32 @@ func Generate(url string, takeScreenshots bool, includePSI bool, includeAIAnalys
 	// Return the generated report.
 	return &report, nil
 }
+
+// takeScreenshot captures a screenshot with the given options and stores it and its
+// thumbnail in the report under the name.
+func takeScreenshot(ctx context.Context, report *types.Report, name string, opts screenshot.Options) error {
+	shot, err := screenshot.Take(ctx, opts)
+	if err != nil || shot == nil {
+		return err
+	}
+	if shot.Truncated {
+		log.Printf("%s screenshot was cut at %.0fpx\n", name, shot.Clip.Height)
+	}
+	report.Screenshots[name] = shot.Data
+	report.ScreenshotClips[name] = shot.Clip
+	if shot.Thumbnail != "" {
+		report.Thumbnails[name] = shot.Thumbnail
+	}
+	return nil
+}
+
+// annotateScreenshot draws the markers on a full-page screenshot of the current layout and
+// adds it to the report.
+func annotateScreenshot(ctx context.Context, report *types.Report, name string, markers []types.AnnotationMarker) error {
+	annotated, err := annotate.Capture(ctx, name, markers)
+	if err != nil || annotated == nil {
+		return err
+	}
+	report.Annotated = append(report.Annotated, annotated)
+	return nil
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"errors"
+
+	"uxlyze/analyzer/pkg/filmstrip"
+	"uxlyze/analyzer/pkg/screenshot"
+	"uxlyze/analyzer/pkg/types"
+)
+
+// Options configure which steps Generate runs and how.
+type Options struct {
+	TakeScreenshots   bool               // Capture the Desktop, Mobile, section and fold screenshots
+	IncludePSI        bool               // Fetch PageSpeed Insights
+	IncludeAIAnalysis bool               // Run the Gemini UX analysis
+	SubmitEmptyForms  bool               // Submit each form empty to capture its validation behavior; see AnalyzeForms
+	AutoScroll        bool               // Scroll through the page before analysis so lazy-loaded content is included
+	RecordFilmstrip   bool               // Record a screencast of the navigation as a filmstrip
+	FilmstripExport   filmstrip.Export   // Also export the recording as an animated GIF or a frame sequence
+	Screenshot        screenshot.Options // Mode, format, quality and thumbnail width of the Desktop and Mobile screenshots
+}
+
+// OptionsFromConfig converts the report settings of a request into options. Invalid
+// screenshot or filmstrip settings are reset to their defaults and reported in the error, so
+// callers can either reject the request or carry on with the defaults.
+func OptionsFromConfig(config types.ReportConfig) (Options, error) {
+	opts := Options{
+		TakeScreenshots:   config.IncludePreview,
+		IncludePSI:        config.IncludePSI,
+		IncludeAIAnalysis: config.IncludeAIAnalysis,
+		SubmitEmptyForms:  config.SubmitEmptyForms,
+		AutoScroll:        config.AutoScroll,
+		RecordFilmstrip:   config.Filmstrip,
+		FilmstripExport:   filmstrip.Export(config.FilmstripExport),
+	}
+
+	var errs []error
+	var err error
+	opts.Screenshot, err = screenshot.OptionsFromConfig(config.ScreenshotConfig)
+	if err != nil {
+		opts.Screenshot = screenshot.Options{}
+		errs = append(errs, err)
+	}
+	if err := opts.FilmstripExport.Validate(); err != nil {
+		opts.FilmstripExport = filmstrip.ExportNone
+		errs = append(errs, err)
+	}
+	return opts, errors.Join(errs...)
+}
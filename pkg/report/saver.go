@@ -4,15 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
-	"text/template"
 	"time"
 
+	"uxlyze/analyzer/pkg/storage"
 	"uxlyze/analyzer/pkg/types"
 )
 
@@ -43,6 +45,9 @@ func Save(report *types.Report, filename string) error {
 	return nil
 }
 
+// cssColorPattern matches the hex, functional and named colors the analyzers report.
+var cssColorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|(rgb|rgba|hsl|hsla)\([0-9a-z.,%/ ]+\)|[a-zA-Z]+)$`)
+
 func generateHTMLContent(report *types.Report, psi *types.PageSpeedInsights) (string, error) {
 	log.Println("Starting to generate HTML content...")
 
@@ -53,6 +58,42 @@ func generateHTMLContent(report *types.Report, psi *types.PageSpeedInsights) (st
 		"percentage": func(score float64) string {
 			return fmt.Sprintf("%.0f", score*100)
 		},
+		// Share of a length in percent, for positioning over a scaled image
+		"pct": func(part, whole float64) string {
+			if whole <= 0 {
+				return "0"
+			}
+			return fmt.Sprintf("%.3f", part/whole*100)
+		},
+		// Milliseconds as seconds with two decimals, for the filmstrip timings
+		"seconds": func(ms float64) string {
+			return fmt.Sprintf("%.2f", ms/1000)
+		},
+		// Page colors for style attributes. html/template rejects parentheses in CSS values,
+		// so known color syntax is passed through and anything else is dropped.
+		"cssColor": func(color string) template.CSS {
+			color = strings.TrimSpace(color)
+			if !cssColorPattern.MatchString(color) {
+				return "transparent"
+			}
+			return template.CSS(color)
+		},
+		// Images are either inline base64 PNG, JPEG, WebP or GIF or artifact store URLs. Data
+		// URIs are marked safe since html/template only passes http(s) URLs through.
+		"imageSrc": func(image string) template.URL {
+			switch {
+			case storage.IsReference(image):
+				return template.URL(image)
+			case strings.HasPrefix(image, "/9j/"):
+				return template.URL("data:image/jpeg;base64," + image)
+			case strings.HasPrefix(image, "UklGR"):
+				return template.URL("data:image/webp;base64," + image)
+			case strings.HasPrefix(image, "R0lGOD"):
+				return template.URL("data:image/gif;base64," + image)
+			default:
+				return template.URL("data:image/png;base64," + image)
+			}
+		},
 	}
 
 	tmpl, err := template.New("report_template.html").Funcs(funcMap).ParseFiles(templatePath)
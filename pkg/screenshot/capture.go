@@ -2,43 +2,29 @@ package screenshot
 
 import (
 	"context"
-	"encoding/base64"
-	"fmt"
 	"time"
 
-	"github.com/chromedp/chromedp"
+	"uxlyze/analyzer/pkg/types"
 )
 
+// Capture screenshots the element matching the selector as PNG after giving the page two
+// seconds to settle. It returns an empty string when no element matches.
 func Capture(ctx context.Context, selector string) (string, error) {
-	var buf []byte
-	var nodeFound bool
-
-	// Create a new context with a longer timeout
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	err := chromedp.Run(ctx,
-		chromedp.Evaluate(fmt.Sprintf(`document.querySelector('%s') !== null`, selector), &nodeFound),
-	)
-
-	if err != nil {
+	shot, err := Take(ctx, Options{Mode: ModeElement, Selector: selector, ThumbnailWidth: -1, Delay: 2 * time.Second})
+	if err != nil || shot == nil {
 		return "", err
 	}
 
-	if !nodeFound {
-		return "", nil
-	}
-
-	err = chromedp.Run(ctx,
-		chromedp.WaitVisible(selector, chromedp.ByQuery),
-		// Sleep for 2 seconds to allow the page to load
-		chromedp.Sleep(2*time.Second),
-		chromedp.Screenshot(selector, &buf, chromedp.NodeVisible, chromedp.ByQuery),
-	)
+	return shot.Data, nil
+}
 
-	if err != nil {
+// CaptureArea screenshots a page-relative rectangle given in CSS pixels, including any part
+// of it that lies below the current viewport.
+func CaptureArea(ctx context.Context, x, y, width, height float64) (string, error) {
+	shot, err := Take(ctx, Options{Mode: ModeClip, Clip: types.Rect{X: x, Y: y, Width: width, Height: height}, ThumbnailWidth: -1})
+	if err != nil || shot == nil {
 		return "", err
 	}
 
-	return base64.StdEncoding.EncodeToString(buf), nil
+	return shot.Data, nil
 }
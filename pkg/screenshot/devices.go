@@ -0,0 +1,36 @@
+package screenshot
+
+import (
+	"context"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Device is a viewport profile the page can be emulated in.
+type Device struct {
+	Name   string
+	Width  int64
+	Height int64
+	Scale  float64
+	Mobile bool
+}
+
+var (
+	DesktopDevice = Device{Name: "Desktop", Width: 1366, Height: 768, Scale: 1}
+	TabletDevice  = Device{Name: "Tablet", Width: 768, Height: 1024, Scale: 2, Mobile: true}
+	MobileDevice  = Device{Name: "Mobile", Width: 375, Height: 812, Scale: 2, Mobile: true}
+)
+
+// Emulate switches the viewport to the given device profile.
+func Emulate(ctx context.Context, device Device) error {
+	opts := []chromedp.EmulateViewportOption{chromedp.EmulateScale(device.Scale), chromedp.EmulatePortrait}
+	if device.Mobile {
+		opts = append(opts, chromedp.EmulateMobile, chromedp.EmulateTouch)
+	}
+	return chromedp.Run(ctx, chromedp.EmulateViewport(device.Width, device.Height, opts...))
+}
+
+// ResetEmulation restores the default desktop viewport.
+func ResetEmulation(ctx context.Context) error {
+	return chromedp.Run(ctx, chromedp.EmulateViewport(0, 0))
+}
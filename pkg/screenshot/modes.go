@@ -0,0 +1,250 @@
+package screenshot
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"uxlyze/analyzer/pkg/types"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// Mode selects the part of the page a screenshot covers.
+type Mode string
+
+const (
+	ModeViewport Mode = "viewport" // The visible viewport at the current scroll position
+	ModeFullPage Mode = "fullPage" // The whole document, rendered beyond the viewport without resizing it
+	ModeElement  Mode = "element"  // The bounding box of the element matching Options.Selector
+	ModeClip     Mode = "clip"     // The page-relative rectangle in Options.Clip
+)
+
+// legacyModeBoth is the screenshotMode of older request payloads, from before modes existed.
+// Desktop and mobile screenshots are always taken, so it stands for full-page captures.
+const legacyModeBoth = "both"
+
+// Format is the image encoding of a screenshot.
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatJPEG Format = "jpeg"
+	FormatWebP Format = "webp"
+)
+
+// DefaultQuality is the JPEG and WebP quality used when none is given.
+const DefaultQuality = 80
+
+// DefaultThumbnailWidth is the width in pixels of thumbnails when none is given.
+const DefaultThumbnailWidth = 320
+
+// maxCaptureSize is the largest image dimension in device pixels Chrome renders in one
+// capture; longer full-page screenshots are cut at this height.
+const maxCaptureSize = 16384
+
+// Options configure a screenshot.
+type Options struct {
+	Mode           Mode          // What to capture; ModeFullPage when empty
+	Selector       string        // Element to capture in ModeElement
+	Clip           types.Rect    // Page-relative rectangle in CSS pixels for ModeClip
+	Format         Format        // Image encoding; FormatPNG when empty
+	Quality        int64         // 1-100 for JPEG and WebP, DefaultQuality when 0; ignored for PNG
+	ThumbnailWidth int64         // Thumbnail width in pixels, DefaultThumbnailWidth when 0; negative disables thumbnails
+	Delay          time.Duration // Wait before capturing, e.g. for late content and animations
+}
+
+// Shot is a captured screenshot.
+type Shot struct {
+	Data      string     // Base64 image in the requested format
+	Thumbnail string     // Base64 image scaled down to the thumbnail width, same format
+	Format    Format     // Image encoding of Data and Thumbnail
+	Clip      types.Rect // Captured page area in CSS pixels
+	Truncated bool       // Whether a full page was cut at the largest size Chrome can capture
+}
+
+// Validate reports unknown modes and formats and missing mode parameters.
+func (o Options) Validate() error {
+	switch o.Mode {
+	case "", ModeViewport, ModeFullPage:
+	case ModeElement:
+		if o.Selector == "" {
+			return fmt.Errorf("screenshot mode %q needs a selector", o.Mode)
+		}
+	case ModeClip:
+		if o.Clip.Width <= 0 || o.Clip.Height <= 0 {
+			return fmt.Errorf("screenshot mode %q needs a clip rectangle", o.Mode)
+		}
+	default:
+		return fmt.Errorf("unknown screenshot mode %q", o.Mode)
+	}
+	switch o.Format {
+	case "", FormatPNG, FormatJPEG, FormatWebP:
+	default:
+		return fmt.Errorf("unknown screenshot format %q", o.Format)
+	}
+	if o.Quality < 0 || o.Quality > 100 {
+		return fmt.Errorf("screenshot quality %d is outside 1-100", o.Quality)
+	}
+	return nil
+}
+
+func (o Options) withDefaults() Options {
+	if o.Mode == "" {
+		o.Mode = ModeFullPage
+	}
+	if o.Format == "" {
+		o.Format = FormatPNG
+	}
+	if o.Quality == 0 {
+		o.Quality = DefaultQuality
+	}
+	if o.ThumbnailWidth == 0 {
+		o.ThumbnailWidth = DefaultThumbnailWidth
+	}
+	return o
+}
+
+// Take captures a screenshot as configured by the options. It returns nil without an error
+// when the element of ModeElement does not exist or has no size.
+func Take(ctx context.Context, opts Options) (*Shot, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if opts.Delay > 0 {
+		if err := chromedp.Run(ctx, chromedp.Sleep(opts.Delay)); err != nil {
+			return nil, err
+		}
+	}
+
+	var pixelRatio float64
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`window.devicePixelRatio`, &pixelRatio)); err != nil {
+		return nil, err
+	}
+	pixelRatio = math.Max(pixelRatio, 1)
+
+	clip, err := resolveClip(ctx, opts)
+	if err != nil || clip == nil {
+		return nil, err
+	}
+	shot := &Shot{Format: opts.Format, Clip: *clip}
+	if maxHeight := math.Floor(maxCaptureSize / pixelRatio); shot.Clip.Height > maxHeight {
+		shot.Clip.Height = maxHeight
+		shot.Truncated = true
+	}
+
+	shot.Data, err = captureClip(ctx, shot.Clip, 1, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ThumbnailWidth > 0 {
+		if width := shot.Clip.Width * pixelRatio; width > float64(opts.ThumbnailWidth) {
+			shot.Thumbnail, err = captureClip(ctx, shot.Clip, float64(opts.ThumbnailWidth)/width, opts)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			shot.Thumbnail = shot.Data
+		}
+	}
+	return shot, nil
+}
+
+// resolveClip turns the mode into the page-relative rectangle to capture.
+func resolveClip(ctx context.Context, opts Options) (*types.Rect, error) {
+	switch opts.Mode {
+	case ModeClip:
+		clip := opts.Clip
+		return &clip, nil
+
+	case ModeElement:
+		selector, err := json.Marshal(opts.Selector)
+		if err != nil {
+			return nil, err
+		}
+		var rect types.Rect
+		err = chromedp.Run(ctx, chromedp.Evaluate(`
+			(function() {
+				const el = document.querySelector(`+string(selector)+`);
+				if (!el) return {x: 0, y: 0, width: 0, height: 0};
+				const r = el.getBoundingClientRect();
+				return {x: r.left + window.scrollX, y: r.top + window.scrollY, width: r.width, height: r.height};
+			})()
+		`, &rect))
+		if err != nil {
+			return nil, err
+		}
+		if rect.Width < 1 || rect.Height < 1 {
+			return nil, nil
+		}
+		return &rect, nil
+
+	default:
+		var clip types.Rect
+		err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			_, _, _, _, visual, content, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return err
+			}
+			if opts.Mode == ModeViewport {
+				clip = types.Rect{X: visual.PageX, Y: visual.PageY, Width: visual.ClientWidth, Height: visual.ClientHeight}
+			} else {
+				clip = types.Rect{Width: content.Width, Height: content.Height}
+			}
+			return nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return &clip, nil
+	}
+}
+
+// captureClip renders a page-relative rectangle, beyond the viewport if needed, scaled by
+// the given factor.
+func captureClip(ctx context.Context, clip types.Rect, scale float64, opts Options) (string, error) {
+	var buf []byte
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		params := page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormat(opts.Format)).
+			WithClip(&page.Viewport{X: clip.X, Y: clip.Y, Width: clip.Width, Height: clip.Height, Scale: scale}).
+			WithCaptureBeyondViewport(opts.Mode != ModeViewport)
+		if opts.Format != FormatPNG {
+			params = params.WithQuality(opts.Quality)
+		}
+		var err error
+		buf, err = params.Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// OptionsFromConfig converts the screenshot settings of a request into capture options.
+func OptionsFromConfig(config types.ScreenshotConfig) (Options, error) {
+	mode := Mode(config.ScreenshotMode)
+	if config.ScreenshotMode == legacyModeBoth {
+		mode = ModeFullPage
+	}
+	opts := Options{
+		Mode:           mode,
+		Selector:       config.ScreenshotSelector,
+		Clip:           config.ScreenshotClip,
+		Format:         Format(config.ScreenshotFormat),
+		Quality:        config.ScreenshotQuality,
+		ThumbnailWidth: config.ThumbnailWidth,
+	}
+	return opts, opts.Validate()
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore writes artifacts to a directory on disk.
+type LocalStore struct {
+	Dir     string // Directory the artifacts are written to
+	BaseURL string // URL the directory is served from; file:// URLs when empty
+}
+
+// NewLocalStore creates the directory if needed and returns a store writing into it.
+func NewLocalStore(dir, baseURL string) (*LocalStore, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{Dir: abs, BaseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Put writes the data to <dir>/<key> unless the file exists already.
+func (s *LocalStore) Put(ctx context.Context, data []byte, contentType string) (string, error) {
+	key := ArtifactKey(data, sniffContentType(data, contentType))
+	path := filepath.Join(s.Dir, key)
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		// Write to a temporary file first so readers never see a partial artifact
+		tmp, err := os.CreateTemp(s.Dir, key+".*.tmp")
+		if err != nil {
+			return "", err
+		}
+		if _, err := tmp.Write(data); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return "", err
+		}
+		if err := tmp.Close(); err != nil {
+			os.Remove(tmp.Name())
+			return "", err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Remove(tmp.Name())
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	return s.url(key), nil
+}
+
+// Get reads an artifact back by its URL.
+func (s *LocalStore) Get(ctx context.Context, ref string) ([]byte, error) {
+	key, err := s.key(ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	return data, err
+}
+
+func (s *LocalStore) url(key string) string {
+	if s.BaseURL != "" {
+		return s.BaseURL + "/" + key
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(s.Dir, key))}).String()
+}
+
+// key recovers the artifact key from a URL returned by Put.
+func (s *LocalStore) key(ref string) (string, error) {
+	prefix := strings.TrimSuffix(s.url("x"), "x")
+	key := strings.TrimPrefix(ref, prefix)
+	if key == ref || key == "" || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	return key, nil
+}
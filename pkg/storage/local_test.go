@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "artifacts"), "")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	data := []byte("\x89PNG\r\n\x1a\n fake image")
+
+	ref, err := store.Put(ctx, data, "")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !strings.HasPrefix(ref, "file://") || !strings.HasSuffix(ref, ArtifactKey(data, "image/png")) {
+		t.Errorf("Put returned %q, want a file:// URL ending in the artifact key", ref)
+	}
+	again, err := store.Put(ctx, data, "")
+	if err != nil || again != ref {
+		t.Errorf("repeated Put = %q, %v; want %q", again, err, ref)
+	}
+
+	entries, err := os.ReadDir(store.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("store holds %d files, want 1", len(entries))
+	}
+
+	got, err := store.Get(ctx, ref)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get returned %q, want %q", got, data)
+	}
+
+	for _, missing := range []string{
+		strings.TrimSuffix(ref, ".png") + ".jpg",
+		"file:///etc/passwd",
+	} {
+		if _, err := store.Get(ctx, missing); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) error = %v, want ErrNotFound", missing, err)
+		}
+	}
+}
+
+func TestLocalStoreBaseURL(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "https://cdn.example.com/artifacts/")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	data := []byte("GIF89a fake image")
+
+	ref, err := store.Put(context.Background(), data, "")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if want := "https://cdn.example.com/artifacts/" + ArtifactKey(data, "image/gif"); ref != want {
+		t.Errorf("Put returned %q, want %q", ref, want)
+	}
+	got, err := store.Get(context.Background(), ref)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Get = %q, %v; want %q", got, err, data)
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"uxlyze/analyzer/pkg/types"
+)
+
+// StoreReportImages moves every base64 image in the report into the store and replaces it
+// with the artifact URL. Images that are already references are left as they are.
+func StoreReportImages(ctx context.Context, store ArtifactStore, report *types.Report) error {
+	return eachReportImage(report, func(name, value string) (string, error) {
+		if value == "" || IsReference(value) {
+			return value, nil
+		}
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return value, fmt.Errorf("decoding %s: %w", name, err)
+		}
+		ref, err := store.Put(ctx, data, "")
+		if err != nil {
+			return value, fmt.Errorf("storing %s: %w", name, err)
+		}
+		return ref, nil
+	})
+}
+
+// LoadReportImages is the reverse of StoreReportImages: it downloads every referenced image
+// and inlines it as base64, e.g. before diffing against a stored baseline report.
+func LoadReportImages(ctx context.Context, store ArtifactStore, report *types.Report) error {
+	return eachReportImage(report, func(name, value string) (string, error) {
+		if !IsReference(value) {
+			return value, nil
+		}
+		data, err := store.Get(ctx, value)
+		if err != nil {
+			return value, fmt.Errorf("loading %s: %w", name, err)
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	})
+}
+
+// eachReportImage replaces every image field of the report with the result of fn. Fields
+// fn fails on keep their value and the first error is returned after all fields were visited.
+func eachReportImage(report *types.Report, fn func(name, value string) (string, error)) error {
+	var firstErr error
+	visit := func(name string, value *string) {
+		updated, err := fn(name, *value)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		*value = updated
+	}
+
+	for name, value := range report.Screenshots {
+		visit("screenshot "+name, &value)
+		report.Screenshots[name] = value
+	}
+	for name, value := range report.Thumbnails {
+		visit("thumbnail "+name, &value)
+		report.Thumbnails[name] = value
+	}
+	for _, section := range report.Sections {
+		if section != nil {
+			visit("section "+section.Name, &section.Screenshot)
+		}
+	}
+	for _, fold := range report.AboveTheFold {
+		if fold != nil {
+			visit("above-the-fold "+fold.Device, &fold.Screenshot)
+		}
+	}
+	for _, annotated := range report.Annotated {
+		if annotated != nil {
+			visit("annotated "+annotated.Name, &annotated.Image)
+		}
+	}
+	if report.VisualRegression != nil {
+		for i := range report.VisualRegression.Diffs {
+			diff := &report.VisualRegression.Diffs[i]
+			visit("diff "+diff.Name, &diff.DiffImage)
+		}
+	}
+	if report.Filmstrip != nil {
+		for i := range report.Filmstrip.Frames {
+			frame := &report.Filmstrip.Frames[i]
+			visit(fmt.Sprintf("filmstrip frame at %.0fms", frame.Time), &frame.Image)
+		}
+		for i := range report.Filmstrip.Sequence {
+			frame := &report.Filmstrip.Sequence[i]
+			visit(fmt.Sprintf("filmstrip sequence frame at %.0fms", frame.Time), &frame.Image)
+		}
+		visit("filmstrip GIF", &report.Filmstrip.GIF)
+	}
+	return firstErr
+}
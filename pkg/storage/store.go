@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when a reference does not point at a stored artifact.
+var ErrNotFound = errors.New("artifact not found")
+
+// ArtifactStore keeps screenshots and other binaries out of the report JSON. Artifacts are
+// addressed by the SHA-256 of their content, so the same image is written only once and the
+// report stores the reference returned by Put instead of the bytes.
+type ArtifactStore interface {
+	// Put stores the data unless an artifact with the same content exists and returns the
+	// URL the report should reference it by.
+	Put(ctx context.Context, data []byte, contentType string) (string, error)
+
+	// Get reads an artifact back by the reference Put returned.
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
+
+// ArtifactKey is the content-addressed name of an artifact: the hex SHA-256 of the data with
+// an extension derived from the content type, e.g. "3a7bd3e2...c9.png".
+func ArtifactKey(data []byte, contentType string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "." + extension(contentType)
+}
+
+// IsReference reports whether an image field of a report holds an artifact reference rather
+// than base64 data.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, "http://") ||
+		strings.HasPrefix(value, "https://") ||
+		strings.HasPrefix(value, "file://")
+}
+
+// NewFromEnv builds the store configured in the environment, or returns nil when artifacts
+// should stay inline in the report.
+//
+//	ARTIFACT_STORE        - "local" or "s3"; anything else keeps images inline
+//	ARTIFACT_DIR          - directory for the local store, "artifacts" by default
+//	ARTIFACT_BASE_URL     - URL the local directory is served from; file:// URLs when empty
+//	S3_ENDPOINT           - e.g. https://s3.eu-central-1.amazonaws.com or http://localhost:9000
+//	S3_REGION             - signing region, "us-east-1" by default
+//	S3_BUCKET             - bucket the artifacts are written to
+//	S3_ACCESS_KEY_ID      - access key
+//	S3_SECRET_ACCESS_KEY  - secret key
+//	S3_PREFIX             - optional key prefix, e.g. "screenshots/"
+//	S3_PUBLIC_URL         - URL the bucket is publicly served from; endpoint/bucket when empty
+func NewFromEnv() (ArtifactStore, error) {
+	switch os.Getenv("ARTIFACT_STORE") {
+	case "local":
+		dir := os.Getenv("ARTIFACT_DIR")
+		if dir == "" {
+			dir = "artifacts"
+		}
+		return NewLocalStore(dir, os.Getenv("ARTIFACT_BASE_URL"))
+	case "s3":
+		store := &S3Store{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			Prefix:          os.Getenv("S3_PREFIX"),
+			PublicURL:       os.Getenv("S3_PUBLIC_URL"),
+		}
+		if store.Endpoint == "" || store.Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 artifact store")
+		}
+		return store, nil
+	default:
+		return nil, nil
+	}
+}
+
+// extension maps a content type to the file extension used in artifact keys.
+func extension(contentType string) string {
+	switch strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]) {
+	case "image/png":
+		return "png"
+	case "image/jpeg":
+		return "jpg"
+	case "image/webp":
+		return "webp"
+	case "image/gif":
+		return "gif"
+	case "application/json":
+		return "json"
+	default:
+		return "bin"
+	}
+}
+
+// sniffContentType sniffs the content type of data when the caller does not know it.
+func sniffContentType(data []byte, declared string) string {
+	if declared != "" {
+		return declared
+	}
+	return http.DetectContentType(data)
+}
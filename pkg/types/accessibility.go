@@ -0,0 +1,22 @@
+package types
+
+// AccessibilityAudit is the result of the deterministic in-page accessibility rule set.
+type AccessibilityAudit struct {
+	RulesChecked int                      `json:"rulesChecked"` // Number of rules evaluated
+	RulesFailed  int                      `json:"rulesFailed"`  // Number of rules with at least one violation
+	ByImpact     map[string]int           `json:"byImpact"`     // Violation counts keyed by impact
+	Violations   []AccessibilityViolation `json:"violations"`   // Individual violations
+}
+
+// AccessibilityViolation is a single failed check on a single element (or the whole page).
+type AccessibilityViolation struct {
+	Rule        string `json:"rule"`        // Rule identifier, e.g. "form-label"
+	Description string `json:"description"` // What the rule checks
+	WCAG        string `json:"wcag"`        // WCAG success criterion, e.g. "1.3.1"
+	Level       string `json:"level"`       // WCAG conformance level (A, AA, AAA)
+	Impact      string `json:"impact"`      // critical, serious, moderate or minor
+	Message     string `json:"message"`     // Element-specific explanation
+	Selector    string `json:"selector"`    // CSS selector of the offending element
+	Snippet     string `json:"snippet"`     // HTML snippet of the offending element
+	Rect        Rect   `json:"rect"`        // Page-relative bounding box
+}
@@ -0,0 +1,21 @@
+package types
+
+// AnnotatedScreenshot is a full-page screenshot with the elements flagged by the analyzers
+// drawn on as numbered boxes colored by severity.
+type AnnotatedScreenshot struct {
+	Name    string             `json:"name"`    // Device the screenshot was taken in, e.g. "Desktop"
+	Width   float64            `json:"width"`   // Page width in CSS pixels
+	Height  float64            `json:"height"`  // Captured page height in CSS pixels
+	Image   string             `json:"image"`   // Base64 PNG with the boxes drawn in
+	Markers []AnnotationMarker `json:"markers"` // Legend, one entry per number
+}
+
+// AnnotationMarker is one numbered box on an annotated screenshot.
+type AnnotationMarker struct {
+	Number   int      `json:"number"`   // Number drawn in the badge, in reading order
+	Severity string   `json:"severity"` // critical, serious, moderate or minor
+	Sources  []string `json:"sources"`  // Report sections that flagged the element, e.g. "contrast"
+	Issues   []string `json:"issues"`   // What is wrong with the element
+	Selector string   `json:"selector"` // CSS selector of the element
+	Rect     Rect     `json:"rect"`     // Page-relative bounding box in CSS pixels
+}
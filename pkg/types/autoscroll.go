@@ -0,0 +1,12 @@
+package types
+
+// AutoScrollResult summarizes the scroll pass that loads lazy content before analysis.
+type AutoScrollResult struct {
+	Steps         int     `json:"steps"`         // Scroll steps taken
+	InitialHeight float64 `json:"initialHeight"` // Document height in CSS pixels before scrolling
+	FinalHeight   float64 `json:"finalHeight"`   // Document height in CSS pixels after scrolling
+	Requests      int     `json:"requests"`      // Network requests started while scrolling
+	StoppedBy     string  `json:"stoppedBy"`     // end, maxHeight, maxSteps or timeout
+	Capped        bool    `json:"capped"`        // Whether a cap ended the pass, e.g. on infinite scroll
+	DurationMs    int64   `json:"durationMs"`    // Time spent scrolling and waiting
+}
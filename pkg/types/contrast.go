@@ -0,0 +1,27 @@
+package types
+
+// ContrastAudit reports WCAG color-contrast results for visible text.
+type ContrastAudit struct {
+	TextElements int             `json:"textElements"` // Visible elements with their own text
+	Unresolved   int             `json:"unresolved"`   // Elements over images/gradients whose background cannot be computed
+	FailingAA    int             `json:"failingAA"`    // Elements below the AA threshold
+	FailingAAA   int             `json:"failingAAA"`   // Elements below the AAA threshold (includes AA failures)
+	Issues       []ContrastIssue `json:"issues"`       // Elements failing at least AAA
+}
+
+// ContrastIssue is a text element whose contrast does not meet WCAG thresholds.
+type ContrastIssue struct {
+	Selector       string  `json:"selector"`       // CSS selector of the element
+	Text           string  `json:"text"`           // First characters of the element's text
+	Foreground     string  `json:"foreground"`     // Effective text color (#rrggbb)
+	Background     string  `json:"background"`     // Effective background color (#rrggbb)
+	Ratio          float64 `json:"ratio"`          // Contrast ratio, 1-21
+	FontSize       float64 `json:"fontSize"`       // Font size in CSS pixels
+	FontWeight     int     `json:"fontWeight"`     // Numeric font weight
+	LargeText      bool    `json:"largeText"`      // Whether WCAG "large text" thresholds apply
+	RequiredAA     float64 `json:"requiredAA"`     // Minimum ratio for AA
+	RequiredAAA    float64 `json:"requiredAAA"`    // Minimum ratio for AAA
+	PassesAA       bool    `json:"passesAA"`       // Whether the element meets AA
+	SuggestedColor string  `json:"suggestedColor"` // Nearest text color that meets AA (AAA if AA already passes)
+	Rect           Rect    `json:"rect"`           // Page-relative bounding box
+}
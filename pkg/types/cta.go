@@ -0,0 +1,41 @@
+package types
+
+// CTAAudit scores the calls to action on the page: how prominent the primary ones are,
+// whether they appear above the fold, how clear their labels are, whether several compete
+// for attention and whether they react to hover and keyboard focus.
+type CTAAudit struct {
+	Score        int        `json:"score"`        // 0-100, complements Gemini's cta_design category
+	Total        int        `json:"total"`        // Buttons and button-like links found
+	PrimaryCount int        `json:"primaryCount"` // CTAs classified as primary
+	FoldHeight   float64    `json:"foldHeight"`   // Viewport height used as the fold, in CSS pixels
+	PrimaryAbove bool       `json:"primaryAbove"` // Whether a primary CTA is visible without scrolling
+	VagueLabels  int        `json:"vagueLabels"`  // CTAs with generic labels such as "Click here"
+	NoHoverState int        `json:"noHoverState"` // Probed CTAs whose style does not change on hover
+	NoFocusState int        `json:"noFocusState"` // Probed CTAs whose style does not change on focus
+	Competing    []CTAGroup `json:"competing"`    // Screens where several different primary CTAs compete
+	CTAs         []CTAInfo  `json:"ctas"`         // All CTAs, most prominent first
+	Issues       []string   `json:"issues"`       // Summary of what lowered the score
+}
+
+// CTAInfo describes one call to action.
+type CTAInfo struct {
+	Label       string   `json:"label"`       // Accessible name of the CTA
+	Selector    string   `json:"selector"`    // CSS selector of the element
+	Tag         string   `json:"tag"`         // Tag name
+	Filled      bool     `json:"filled"`      // Whether it has a solid background (a button rather than a text link)
+	Prominence  float64  `json:"prominence"`  // 0-100 from size, contrast and position
+	Contrast    float64  `json:"contrast"`    // Contrast of the button (or its text, for links) with its surroundings
+	Primary     bool     `json:"primary"`     // Whether it is one of the most prominent CTAs
+	AboveFold   bool     `json:"aboveFold"`   // Whether it is visible without scrolling
+	VagueLabel  bool     `json:"vagueLabel"`  // Whether the label says nothing about the action
+	Probed      bool     `json:"probed"`      // Whether hover/focus states were checked
+	HoverChange []string `json:"hoverChange"` // Style properties that change on hover
+	FocusChange []string `json:"focusChange"` // Style properties that change on focus
+	Rect        Rect     `json:"rect"`        // Page-relative bounding box
+}
+
+// CTAGroup is a set of competing primary CTAs within one screen height.
+type CTAGroup struct {
+	Top    float64  `json:"top"`    // Top of the screen band in CSS pixels
+	Labels []string `json:"labels"` // Distinct labels of the primary CTAs in that band
+}
@@ -0,0 +1,25 @@
+package types
+
+// DesignComparison measures how far the live page deviates from a reference design export.
+type DesignComparison struct {
+	URL                string         `json:"url"`                // Page that was captured
+	Width              int            `json:"width"`              // Compared width in reference image pixels
+	Height             int            `json:"height"`             // Compared height in reference image pixels
+	ViewportWidth      int64          `json:"viewportWidth"`      // Viewport width the page was captured at, in CSS pixels
+	PixelRatio         float64        `json:"pixelRatio"`         // Reference image pixels per CSS pixel, e.g. 2 for @2x exports
+	OffsetX            int            `json:"offsetX"`            // Horizontal shift applied to the capture to align it
+	OffsetY            int            `json:"offsetY"`            // Vertical shift applied to the capture to align it
+	Score              int            `json:"score"`              // 0-100 from the structural similarity
+	Similarity         float64        `json:"similarity"`         // Mean structural similarity (SSIM), 1 for identical images
+	MismatchPercentage float64        `json:"mismatchPercentage"` // Percentage of pixels that differ perceptibly
+	Regions            []DesignRegion `json:"regions"`            // Areas that deviate from the design, worst first
+	Heatmap            string         `json:"heatmap"`            // Base64 PNG: the capture with deviation drawn as a heatmap
+}
+
+// DesignRegion is an area of the page that deviates from the reference design.
+type DesignRegion struct {
+	Rect      Rect    `json:"rect"`      // Bounding box in reference image pixels
+	Score     int     `json:"score"`     // 0-100, lower means a larger deviation
+	Deviation float64 `json:"deviation"` // Mean perceptual color distance in the region (0-1)
+	Selector  string  `json:"selector"`  // CSS selector of the element at the center of the region
+}
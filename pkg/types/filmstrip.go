@@ -0,0 +1,21 @@
+package types
+
+// Filmstrip shows how the page renders while it loads, from screencast frames recorded
+// during navigation.
+type Filmstrip struct {
+	FirstPaint             float64          `json:"firstPaint"`             // ms after navigation start, 0 if not reported
+	FirstContentfulPaint   float64          `json:"firstContentfulPaint"`   // ms after navigation start, 0 if not reported
+	LargestContentfulPaint float64          `json:"largestContentfulPaint"` // ms after navigation start, 0 if not reported
+	Load                   float64          `json:"load"`                   // ms after navigation start until the load event
+	Recorded               int              `json:"recorded"`               // Frames received from the screencast
+	Frames                 []FilmstripFrame `json:"frames"`                 // Frames at fixed intervals and at the key timestamps
+	Sequence               []FilmstripFrame `json:"sequence,omitempty"`     // Every recorded frame, when exported as a frame sequence
+	GIF                    string           `json:"gif,omitempty"`          // Base64 animated GIF of the load, when exported as GIF
+}
+
+// FilmstripFrame is the page as it was shown at a point during the load.
+type FilmstripFrame struct {
+	Time   float64  `json:"time"`   // ms after navigation start
+	Labels []string `json:"labels"` // Key timestamps the frame represents, e.g. "First Contentful Paint"
+	Image  string   `json:"image"`  // Base64 JPEG
+}
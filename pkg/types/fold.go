@@ -0,0 +1,35 @@
+package types
+
+// FoldAnalysis describes what a visitor sees without scrolling on one device profile.
+type FoldAnalysis struct {
+	Device                  string        `json:"device"`                  // Device profile the page was measured in
+	Width                   int64         `json:"width"`                   // Viewport width in CSS pixels
+	Height                  int64         `json:"height"`                  // Viewport height (the fold) in CSS pixels
+	PageHeight              float64       `json:"pageHeight"`              // Document height in CSS pixels
+	VisibleShare            float64       `json:"visibleShare"`            // Share (0-1) of the page height inside the first viewport
+	Headings                []FoldElement `json:"headings"`                // Headings inside the first viewport
+	CTAs                    []FoldElement `json:"ctas"`                    // Buttons and button-like links inside the first viewport
+	Images                  []FoldElement `json:"images"`                  // Images, videos and canvases inside the first viewport
+	TextBlocks              []FoldElement `json:"textBlocks"`              // Paragraphs and list items inside the first viewport
+	Words                   int           `json:"words"`                   // Words of text visible without scrolling
+	ContentShare            float64       `json:"contentShare"`            // Share (0-1) of the fold covered by text, media and controls
+	WhitespaceShare         float64       `json:"whitespaceShare"`         // Share (0-1) of the fold with no content
+	AdShare                 float64       `json:"adShare"`                 // Share (0-1) of the fold covered by ads
+	OverlayShare            float64       `json:"overlayShare"`            // Share (0-1) of the fold covered by fixed or sticky banners, dialogs and bars
+	Overlays                []FoldElement `json:"overlays"`                // Fixed or sticky elements covering part of the fold
+	ValueProposition        string        `json:"valueProposition"`        // The h1, or the largest text near the top when there is none
+	ValuePropositionVisible bool          `json:"valuePropositionVisible"` // Whether it is inside the fold and not covered
+	PrimaryCTA              string        `json:"primaryCta"`              // Label of the largest filled CTA on the page
+	PrimaryCTAVisible       bool          `json:"primaryCtaVisible"`       // Whether it is inside the fold and not covered
+	Issues                  []string      `json:"issues"`                  // Problems with the first screen
+	Screenshot              string        `json:"screenshot,omitempty"`    // Base64 PNG of the top of the page with the fold line drawn in
+}
+
+// FoldElement is an element that is at least partly visible without scrolling.
+type FoldElement struct {
+	Text     string `json:"text"`     // Text or accessible name, shortened
+	Selector string `json:"selector"` // CSS selector of the element
+	Partial  bool   `json:"partial"`  // Whether the fold cuts through the element
+	Covered  bool   `json:"covered"`  // Whether an overlay hides its center
+	Rect     Rect   `json:"rect"`     // Page-relative bounding box
+}
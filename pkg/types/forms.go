@@ -0,0 +1,53 @@
+package types
+
+// FormAudit checks the forms on the page for the usability problems that cost conversions.
+type FormAudit struct {
+	Score      int        `json:"score"`      // 0-100, higher is better
+	FormCount  int        `json:"formCount"`  // Forms found, including a group for fields outside any form
+	FieldCount int        `json:"fieldCount"` // Visible fields across all forms
+	SafeMode   bool       `json:"safeMode"`   // Whether empty forms were submitted to capture validation
+	Forms      []FormInfo `json:"forms"`      // Per-form results
+	Issues     []string   `json:"issues"`     // Explanations for every deduction
+}
+
+// FormInfo describes one form and its fields.
+type FormInfo struct {
+	Name          string          `json:"name"`                 // aria-label, heading, id or submit button text
+	Selector      string          `json:"selector"`             // CSS selector of the form
+	Action        string          `json:"action"`               // action attribute
+	Method        string          `json:"method"`               // method attribute, lower case
+	NoValidate    bool            `json:"noValidate"`           // Whether native validation is turned off
+	FieldCount    int             `json:"fieldCount"`           // Visible fields in the form
+	RequiredCount int             `json:"requiredCount"`        // Fields marked required or aria-required
+	Fields        []FormField     `json:"fields"`               // The visible fields
+	Issues        []string        `json:"issues"`               // Form-level problems
+	Submission    *FormSubmission `json:"submission,omitempty"` // Validation behavior in safe mode
+}
+
+// FormField is a single visible form control.
+type FormField struct {
+	Selector     string   `json:"selector"`     // CSS selector of the field
+	Tag          string   `json:"tag"`          // input, select or textarea
+	Type         string   `json:"type"`         // type attribute for inputs
+	Name         string   `json:"name"`         // name attribute
+	Label        string   `json:"label"`        // Accessible label text
+	LabelSource  string   `json:"labelSource"`  // label, aria-labelledby, aria-label, title, placeholder or empty
+	Placeholder  string   `json:"placeholder"`  // placeholder attribute
+	InputMode    string   `json:"inputMode"`    // inputmode attribute
+	Autocomplete string   `json:"autocomplete"` // autocomplete attribute
+	Purpose      string   `json:"purpose"`      // Recognized purpose as an autocomplete token, e.g. "email"
+	Required     bool     `json:"required"`     // required or aria-required="true"
+	Indicated    bool     `json:"indicated"`    // Whether the label marks the field as required
+	DescribedBy  bool     `json:"describedBy"`  // Whether aria-describedby points at existing elements
+	Issues       []string `json:"issues"`       // Problems with this field
+}
+
+// FormSubmission is what happened when the empty form was submitted in safe mode.
+type FormSubmission struct {
+	Blocked           bool     `json:"blocked"`           // Whether validation stopped the submission
+	InvalidFields     int      `json:"invalidFields"`     // Fields failing native constraint validation
+	AriaInvalid       int      `json:"ariaInvalid"`       // Fields marked aria-invalid="true" afterwards
+	ErrorMessages     []string `json:"errorMessages"`     // Error texts that appeared
+	AssociatedErrors  int      `json:"associatedErrors"`  // Messages linked to a field or announced by a live region
+	FocusMovedToError bool     `json:"focusMovedToError"` // Whether focus moved to an invalid field
+}
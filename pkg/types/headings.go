@@ -0,0 +1,40 @@
+package types
+
+// HeadingOutline is the document outline built from h1-h6 (and role="heading") elements.
+type HeadingOutline struct {
+	H1Count         int                   `json:"h1Count"`         // Number of visible h1 headings
+	Counts          map[string]int        `json:"counts"`          // Visible headings per level, keyed "h1".."h6"
+	Outline         []*HeadingNode        `json:"outline"`         // Top-level nodes of the outline tree
+	LevelStyles     map[string]LevelStyle `json:"levelStyles"`     // Median computed style per heading level
+	SkippedLevels   []HeadingIssue        `json:"skippedLevels"`   // Headings that jump more than one level down
+	EmptyHeadings   []HeadingIssue        `json:"emptyHeadings"`   // Headings without any text
+	InvalidLevels   []HeadingIssue        `json:"invalidLevels"`   // role="heading" elements whose aria-level is not 1-6
+	HierarchyIssues []HeadingIssue        `json:"hierarchyIssues"` // Levels that are not visually smaller/lighter than the level above
+}
+
+// HeadingNode is one heading in the outline tree.
+type HeadingNode struct {
+	Level      int            `json:"level"`      // 1-6
+	Text       string         `json:"text"`       // Heading text
+	Selector   string         `json:"selector"`   // CSS selector of the element
+	FontSize   float64        `json:"fontSize"`   // Computed font size in CSS pixels
+	FontWeight int            `json:"fontWeight"` // Computed numeric font weight
+	Rect       Rect           `json:"rect"`       // Page-relative bounding box
+	Children   []*HeadingNode `json:"children"`   // Headings nested under this one
+}
+
+// LevelStyle is the typical computed style of a heading level.
+type LevelStyle struct {
+	Count      int     `json:"count"`      // Headings of this level
+	FontSize   float64 `json:"fontSize"`   // Median font size in CSS pixels
+	FontWeight int     `json:"fontWeight"` // Median font weight
+}
+
+// HeadingIssue describes a problem with a heading or heading level.
+type HeadingIssue struct {
+	Level    int    `json:"level"`    // Heading level the issue is about
+	Text     string `json:"text"`     // Heading text, if any
+	Selector string `json:"selector"` // CSS selector of the element, if the issue is about one element
+	Message  string `json:"message"`  // Explanation of the problem
+	Rect     Rect   `json:"rect"`     // Page-relative bounding box, if the issue is about one element
+}
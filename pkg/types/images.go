@@ -0,0 +1,44 @@
+package types
+
+// Rect is a page-relative bounding box in CSS pixels.
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// ImageAudit summarizes how images are used on the page.
+type ImageAudit struct {
+	TotalImages       int         `json:"totalImages"`       // Number of <img> elements, including those inside <picture>
+	BackgroundImages  int         `json:"backgroundImages"`  // Number of elements with a CSS background image
+	MissingAlt        int         `json:"missingAlt"`        // Images without an alt attribute
+	GenericAlt        int         `json:"genericAlt"`        // Images whose alt text does not describe anything
+	Oversized         int         `json:"oversized"`         // Images downloaded much larger than they are rendered
+	LegacyFormat      int         `json:"legacyFormat"`      // Images served as JPEG/PNG/GIF/BMP without a modern alternative
+	MissingDimensions int         `json:"missingDimensions"` // Images without width/height attributes
+	NotLazyLoaded     int         `json:"notLazyLoaded"`     // Below-the-fold images without loading="lazy"
+	WastedPixels      int64       `json:"wastedPixels"`      // Natural pixels beyond what is needed for the rendered size
+	Images            []ImageInfo `json:"images"`            // Per-image details
+}
+
+// ImageInfo describes a single image found on the page.
+type ImageInfo struct {
+	Kind           string   `json:"kind"`           // img, picture or background
+	Src            string   `json:"src"`            // Resolved source URL
+	Alt            string   `json:"alt"`            // Alt text, if any
+	HasAlt         bool     `json:"hasAlt"`         // Whether the alt attribute is present at all
+	Decorative     bool     `json:"decorative"`     // Marked decorative via alt="" or role="presentation"
+	Format         string   `json:"format"`         // File format guessed from the URL
+	ModernSources  []string `json:"modernSources"`  // Modern formats offered through <picture> sources
+	RenderedWidth  float64  `json:"renderedWidth"`  // Width in CSS pixels as laid out
+	RenderedHeight float64  `json:"renderedHeight"` // Height in CSS pixels as laid out
+	NaturalWidth   int      `json:"naturalWidth"`   // Intrinsic width of the downloaded file
+	NaturalHeight  int      `json:"naturalHeight"`  // Intrinsic height of the downloaded file
+	HasDimensions  bool     `json:"hasDimensions"`  // Whether width and height attributes are set
+	Loading        string   `json:"loading"`        // Value of the loading attribute
+	BelowFold      bool     `json:"belowFold"`      // Whether the image starts below the first viewport
+	Selector       string   `json:"selector"`       // CSS selector of the element
+	Rect           Rect     `json:"rect"`           // Page-relative bounding box
+	Issues         []string `json:"issues"`         // Problems found for this image
+}
@@ -0,0 +1,27 @@
+package types
+
+// KeyboardAudit describes how the page behaves when navigated with Tab and Shift+Tab.
+type KeyboardAudit struct {
+	FocusOrder         []FocusStop `json:"focusOrder"`         // Elements in the order Tab reaches them
+	Unreachable        []FocusStop `json:"unreachable"`        // Interactive elements Tab never reaches; not checked when Truncated
+	Truncated          bool        `json:"truncated"`          // Whether the Tab walk hit the step limit before focus cycled
+	MissingFocusStyle  int         `json:"missingFocusStyle"`  // Focus stops with no visible focus indicator
+	FocusTrap          bool        `json:"focusTrap"`          // Whether focus got stuck cycling inside part of the page
+	TrappedIn          []string    `json:"trappedIn"`          // Selectors of the elements focus cycled between
+	ReverseOrderIssues int         `json:"reverseOrderIssues"` // Shift+Tab stops that differ from the reversed Tab order
+	HasSkipLink        bool        `json:"hasSkipLink"`        // Whether one of the first focus stops is a skip link
+	SkipLinkTarget     string      `json:"skipLinkTarget"`     // Fragment the skip link points to
+	SkipLinkValid      bool        `json:"skipLinkValid"`      // Whether the skip link target exists
+}
+
+// FocusStop is one element reached (or expected to be reached) by keyboard focus.
+type FocusStop struct {
+	Index        int      `json:"index"`        // 1-based position in the focus order (0 if unreachable)
+	Selector     string   `json:"selector"`     // CSS selector of the element
+	Tag          string   `json:"tag"`          // Lower-case tag name
+	Name         string   `json:"name"`         // Accessible name
+	TabIndex     int      `json:"tabIndex"`     // Effective tabIndex property
+	VisibleFocus bool     `json:"visibleFocus"` // Whether focusing changed outline, box-shadow, border, background or text decoration
+	FocusChanges []string `json:"focusChanges"` // Which computed properties changed on focus
+	Rect         Rect     `json:"rect"`         // Page-relative bounding box
+}
@@ -0,0 +1,39 @@
+package types
+
+// MobileFriendliness is a structured mobile-readiness result with a 0-100 score.
+type MobileFriendliness struct {
+	Score            int                `json:"score"`            // 0-100, higher is better
+	Passed           bool               `json:"passed"`           // Whether the score meets the mobile-friendly threshold
+	Viewport         ViewportDirectives `json:"viewport"`         // Parsed viewport meta tag
+	Overflow         []WidthOverflow    `json:"overflow"`         // Horizontal overflow per tested phone width
+	LegibleFontShare float64            `json:"legibleFontShare"` // Share (0-1) of visible text at or above MinFontSize
+	MinFontSize      float64            `json:"minFontSize"`      // Font size in CSS pixels considered legible
+	WideElements     []WideElement      `json:"wideElements"`     // Fixed-width elements wider than the viewport
+	Reasons          []string           `json:"reasons"`          // Explanations for every deduction
+}
+
+// ViewportDirectives are the directives of <meta name="viewport">.
+type ViewportDirectives struct {
+	Present      bool   `json:"present"`      // Whether the meta tag exists
+	Content      string `json:"content"`      // Raw content attribute
+	Width        string `json:"width"`        // width directive, e.g. "device-width" or "1024"
+	InitialScale string `json:"initialScale"` // initial-scale directive
+	MaximumScale string `json:"maximumScale"` // maximum-scale directive
+	UserScalable string `json:"userScalable"` // user-scalable directive
+	ZoomDisabled bool   `json:"zoomDisabled"` // user-scalable=no or maximum-scale below 2
+}
+
+// WidthOverflow records whether the page scrolls horizontally at a given viewport width.
+type WidthOverflow struct {
+	Width       int64 `json:"width"`       // Emulated viewport width in CSS pixels
+	ScrollWidth int64 `json:"scrollWidth"` // Document scroll width at that viewport
+	Overflows   bool  `json:"overflows"`   // Whether content is wider than the viewport
+}
+
+// WideElement is an element with a fixed width larger than the phone viewport.
+type WideElement struct {
+	Selector      string  `json:"selector"`      // CSS selector of the element
+	Width         float64 `json:"width"`         // Rendered width in CSS pixels
+	ViewportWidth int64   `json:"viewportWidth"` // Viewport width it was measured at
+	Rect          Rect    `json:"rect"`          // Page-relative bounding box
+}
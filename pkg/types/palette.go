@@ -0,0 +1,46 @@
+package types
+
+// Color roles in the measured palette.
+const (
+	ColorRoleText       = "text"
+	ColorRoleBackground = "background"
+	ColorRoleBorder     = "border"
+	ColorRoleAccent     = "accent"
+)
+
+// ColorPalette is the measured color palette of the page, clustered in CIELAB and
+// weighted by how much of the rendered page each color covers.
+type ColorPalette struct {
+	DistinctColors int                `json:"distinctColors"`             // Distinct computed colors before clustering
+	TotalArea      float64            `json:"totalArea"`                  // Painted area of all samples in CSS px²
+	Colors         []PaletteColor     `json:"colors"`                     // Clusters, largest area first
+	ByRole         map[string]int     `json:"byRole"`                     // Number of clusters per role
+	Gemini         *PaletteComparison `json:"geminiComparison,omitempty"` // Agreement with Gemini's ColorScheme, when AI analysis ran
+}
+
+// PaletteColor is one cluster of perceptually similar colors.
+type PaletteColor struct {
+	Hex      string     `json:"hex"`      // Representative color (the most used member)
+	Lab      [3]float64 `json:"lab"`      // Area-weighted CIELAB centroid
+	Role     string     `json:"role"`     // text, background, border or accent
+	Area     float64    `json:"area"`     // Painted area in CSS px²
+	Share    float64    `json:"share"`    // Share (0-1) of the total painted area
+	Elements int        `json:"elements"` // Elements painting this color
+	Members  []string   `json:"members"`  // Distinct hex values merged into the cluster
+}
+
+// PaletteComparison compares the colors Gemini reported with the measured palette.
+type PaletteComparison struct {
+	Agreement  float64      `json:"agreement"`  // Share (0-1) of Gemini's colors found in the measured palette
+	Matches    []ColorMatch `json:"matches"`    // One entry per color Gemini reported
+	Unreported []string     `json:"unreported"` // Prominent measured colors Gemini did not mention
+}
+
+// ColorMatch pairs a color reported by Gemini with the nearest measured cluster.
+type ColorMatch struct {
+	Scheme   string  `json:"scheme"`   // primary, secondary or accent
+	Reported string  `json:"reported"` // Color as reported by Gemini
+	Nearest  string  `json:"nearest"`  // Hex of the nearest measured cluster
+	DeltaE   float64 `json:"deltaE"`   // CIEDE2000 distance to that cluster
+	Matched  bool    `json:"matched"`  // Whether the distance is small enough to count as the same color
+}
@@ -0,0 +1,24 @@
+package types
+
+// ReadabilityMetrics are text statistics computed from the visible main content of the page.
+type ReadabilityMetrics struct {
+	Language            string         `json:"language"`            // ISO 639-1 code the metrics were computed for
+	LanguageSource      string         `json:"languageSource"`      // "lang attribute" or "detected"
+	Words               int            `json:"words"`               // Word count of the main content
+	Sentences           int            `json:"sentences"`           // Sentence count of the main content
+	Paragraphs          int            `json:"paragraphs"`          // Paragraph count of the main content
+	FleschReadingEase   float64        `json:"fleschReadingEase"`   // 0-100, higher is easier (language-specific variant)
+	FleschFormula       string         `json:"fleschFormula"`       // Name of the reading-ease formula used
+	GradeLevel          float64        `json:"gradeLevel"`          // School grade needed to understand the text
+	GradeFormula        string         `json:"gradeFormula"`        // Name of the grade-level formula used
+	GunningFog          float64        `json:"gunningFog"`          // Gunning fog index
+	AvgSentenceLength   float64        `json:"avgSentenceLength"`   // Words per sentence
+	AvgWordLength       float64        `json:"avgWordLength"`       // Characters per word
+	AvgSyllablesPerWord float64        `json:"avgSyllablesPerWord"` // Syllables per word
+	PassiveVoiceRatio   float64        `json:"passiveVoiceRatio"`   // Share (0-1) of sentences in passive voice
+	ParagraphLengths    map[string]int `json:"paragraphLengths"`    // Paragraph counts bucketed by word count
+	AvgParagraphLength  float64        `json:"avgParagraphLength"`  // Words per paragraph
+	LongestParagraph    int            `json:"longestParagraph"`    // Words in the longest paragraph
+	ReadingTimeMinutes  float64        `json:"readingTimeMinutes"`  // Estimated reading time at the language's average speed
+	Level               string         `json:"level"`               // Human readable difficulty, e.g. "Fairly easy"
+}
@@ -0,0 +1,15 @@
+package types
+
+// ReportConfig holds the report options of a request. It is embedded in the API request
+// body and in the stored report configuration, so both use the same keys.
+type ReportConfig struct {
+	IncludePreview    bool   `json:"includePreview"`    // Capture the Desktop, Mobile, section and fold screenshots
+	IncludePSI        bool   `json:"includePSI"`        // Fetch PageSpeed Insights
+	IncludeAIAnalysis bool   `json:"includeAIAnalysis"` // Run the Gemini UX analysis
+	SubmitEmptyForms  bool   `json:"submitEmptyForms"`  // Submit forms empty on local fixtures, when the server allows it
+	AutoScroll        bool   `json:"autoScroll"`        // Scroll through the page first to load lazy content
+	Filmstrip         bool   `json:"filmstrip"`         // Record a page-load filmstrip
+	FilmstripExport   string `json:"filmstripExport"`   // "", "gif" or "frames"
+
+	ScreenshotConfig
+}
@@ -0,0 +1,12 @@
+package types
+
+// ScreenshotConfig is the per-request screenshot setting. It is embedded in request bodies
+// so its keys sit next to the other report options.
+type ScreenshotConfig struct {
+	ScreenshotMode     string `json:"screenshotMode"`     // viewport, fullPage, element or clip; fullPage when empty or the legacy "both"
+	ScreenshotSelector string `json:"screenshotSelector"` // Element to capture in element mode
+	ScreenshotClip     Rect   `json:"screenshotClip"`     // Page-relative rectangle in CSS pixels for clip mode
+	ScreenshotFormat   string `json:"screenshotFormat"`   // png, jpeg or webp; png when empty
+	ScreenshotQuality  int64  `json:"screenshotQuality"`  // 1-100 for jpeg and webp
+	ThumbnailWidth     int64  `json:"thumbnailWidth"`     // Thumbnail width in pixels; negative disables thumbnails
+}
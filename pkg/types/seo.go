@@ -0,0 +1,28 @@
+package types
+
+// SEO check statuses.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// SEOAudit is the typed result of the on-page SEO checks.
+type SEOAudit struct {
+	Score     int               `json:"score"`     // Weighted score 0-100
+	Title     string            `json:"title"`     // Document title
+	Canonical string            `json:"canonical"` // Canonical URL, if any
+	Checks    []SEOCheck        `json:"checks"`    // Individual checks
+	Hreflang  map[string]string `json:"hreflang"`  // hreflang code to URL
+	Meta      map[string]string `json:"meta"`      // All meta name/property values
+}
+
+// SEOCheck is a single SEO check with its outcome.
+type SEOCheck struct {
+	ID      string  `json:"id"`      // Stable identifier, e.g. "meta-description"
+	Name    string  `json:"name"`    // Human readable name
+	Status  string  `json:"status"`  // pass, warn or fail
+	Weight  float64 `json:"weight"`  // Contribution to the SEO score
+	Message string  `json:"message"` // Explanation of the outcome
+	Value   string  `json:"value"`   // Observed value, if useful
+}
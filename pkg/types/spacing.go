@@ -0,0 +1,42 @@
+package types
+
+// SpacingAudit describes how consistently margins, paddings and gaps are used across the page.
+type SpacingAudit struct {
+	GridUnit            int              `json:"gridUnit"`            // Best fitting spacing unit in CSS pixels, e.g. 4 or 8
+	OnGrid              bool             `json:"onGrid"`              // Whether enough values fit the unit to call it a grid
+	GridAdherence       float64          `json:"gridAdherence"`       // Share (0-1) of spacing declarations that are multiples of the unit
+	DistinctValues      int              `json:"distinctValues"`      // Distinct non-zero spacing values
+	Scale               []SpacingValue   `json:"scale"`               // Spacing values in use, smallest first
+	OffGrid             []SpacingValue   `json:"offGrid"`             // Values that are not multiples of the unit, most used first
+	InconsistentGutters []GutterIssue    `json:"inconsistentGutters"` // Containers whose children are spaced unevenly
+	MisalignedSiblings  []AlignmentIssue `json:"misalignedSiblings"`  // Siblings that are a few pixels off a shared edge
+}
+
+// SpacingValue is one spacing value and where it is used.
+type SpacingValue struct {
+	Value    float64 `json:"value"`    // Spacing in CSS pixels
+	Count    int     `json:"count"`    // Declarations using this value
+	Margin   int     `json:"margin"`   // Of which margins
+	Padding  int     `json:"padding"`  // Of which paddings
+	Gap      int     `json:"gap"`      // Of which flex/grid gaps
+	Selector string  `json:"selector"` // Example element using the value
+}
+
+// GutterIssue is a container whose children are separated by different amounts of space.
+type GutterIssue struct {
+	Selector string    `json:"selector"` // CSS selector of the container
+	Axis     string    `json:"axis"`     // row or column
+	Gaps     []float64 `json:"gaps"`     // Rendered space between consecutive children
+	Message  string    `json:"message"`  // Explanation of the problem
+	Rect     Rect      `json:"rect"`     // Page-relative bounding box of the container
+}
+
+// AlignmentIssue is a child that nearly, but not exactly, lines up with its siblings.
+type AlignmentIssue struct {
+	Parent   string  `json:"parent"`   // CSS selector of the container
+	Selector string  `json:"selector"` // CSS selector of the misaligned child
+	Edge     string  `json:"edge"`     // Shared alignment line: left, center, right, top, middle or bottom
+	Offset   float64 `json:"offset"`   // Distance from the line the other siblings share
+	Message  string  `json:"message"`  // Explanation of the problem
+	Rect     Rect    `json:"rect"`     // Page-relative bounding box of the child
+}
@@ -0,0 +1,30 @@
+package types
+
+// StructuredDataAudit lists the JSON-LD, Microdata and RDFa items found on the page and
+// how they validate against the schema.org types that drive rich results.
+type StructuredDataAudit struct {
+	Counts              map[string]int        `json:"counts"`              // Items per format: json-ld, microdata, rdfa
+	Items               []StructuredDataItem  `json:"items"`               // Top-level items, including @graph entries
+	ParseErrors         []StructuredDataError `json:"parseErrors"`         // Blocks that could not be parsed
+	EligibleRichResults []string              `json:"eligibleRichResults"` // Rich result types the page qualifies for
+}
+
+// StructuredDataItem is one structured data item in normalized form.
+type StructuredDataItem struct {
+	Format             string                 `json:"format"`             // json-ld, microdata or rdfa
+	Type               string                 `json:"type"`               // schema.org type without the vocabulary prefix
+	Properties         map[string]interface{} `json:"properties"`         // Normalized property tree; nested items carry an "@type" key
+	Validated          bool                   `json:"validated"`          // Whether the type is one of the validated types
+	MissingRequired    []string               `json:"missingRequired"`    // Required properties that are absent
+	MissingRecommended []string               `json:"missingRecommended"` // Recommended properties that are absent
+	Issues             []string               `json:"issues"`             // Type-specific problems, e.g. a breadcrumb without position
+	RichResult         string                 `json:"richResult"`         // Rich result type this item can produce, if any
+	Eligible           bool                   `json:"eligible"`           // Whether the item is complete enough for that rich result
+}
+
+// StructuredDataError describes a structured data block that could not be used.
+type StructuredDataError struct {
+	Format  string `json:"format"`  // json-ld, microdata or rdfa
+	Message string `json:"message"` // Parser error or reason the block was skipped
+	Snippet string `json:"snippet"` // Start of the offending block as raw page markup; escape before rendering
+}
@@ -0,0 +1,25 @@
+package types
+
+// TapTargetAudit reports clickable elements that are hard to hit on a touch screen.
+type TapTargetAudit struct {
+	Device       string      `json:"device"`       // Device profile the page was measured in
+	ViewportSize int64       `json:"viewportSize"` // Viewport width in CSS pixels
+	MinSize      float64     `json:"minSize"`      // Minimum recommended target size in CSS pixels
+	TotalTargets int         `json:"totalTargets"` // Visible clickable elements measured
+	TooSmall     int         `json:"tooSmall"`     // Targets below MinSize in either dimension
+	TooClose     int         `json:"tooClose"`     // Small targets whose touch area overlaps a neighbour
+	Overlapping  int         `json:"overlapping"`  // Targets whose boxes overlap another target
+	Offenders    []TapTarget `json:"offenders"`    // Targets with at least one issue
+}
+
+// TapTarget is a single clickable element measured in the mobile profile.
+type TapTarget struct {
+	Selector        string   `json:"selector"`        // CSS selector of the element
+	Text            string   `json:"text"`            // Accessible name of the element
+	Width           float64  `json:"width"`           // Width in CSS pixels
+	Height          float64  `json:"height"`          // Height in CSS pixels
+	NearestDistance float64  `json:"nearestDistance"` // Gap to the closest other target in CSS pixels
+	NearestSelector string   `json:"nearestSelector"` // Selector of the closest other target
+	Issues          []string `json:"issues"`          // Problems found for this target
+	Rect            Rect     `json:"rect"`            // Page-relative bounding box
+}
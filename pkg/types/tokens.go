@@ -0,0 +1,29 @@
+package types
+
+// DesignTokens is the de-facto design system of the page, derived from the measured
+// palette, typography and spacing plus the border radii and shadows in use.
+type DesignTokens struct {
+	Colors       []DesignToken `json:"colors"`       // Palette colors, named by role
+	FontFamilies []DesignToken `json:"fontFamilies"` // Font families, most used first
+	FontSizes    []DesignToken `json:"fontSizes"`    // Type scale, named relative to the base size
+	FontWeights  []DesignToken `json:"fontWeights"`  // Font weights in use
+	Spacing      []DesignToken `json:"spacing"`      // Spacing scale, named in 4px units like Tailwind
+	Radii        []DesignToken `json:"radii"`        // Border radii, smallest first
+	Shadows      []DesignToken `json:"shadows"`      // Box shadows, subtlest first
+	Exports      TokenExports  `json:"exports"`      // The tokens rendered in common formats
+}
+
+// DesignToken is a single named design value.
+type DesignToken struct {
+	Name  string `json:"name"`  // Token name within its group, e.g. "text-1" or "lg"
+	Value string `json:"value"` // CSS value, e.g. "#1a1a1a", "16px" or "0px 1px 3px rgba(0, 0, 0, 0.1)"
+	Type  string `json:"type"`  // W3C design token type: color, fontFamily, dimension, fontWeight or shadow
+	Usage int    `json:"usage"` // How often the value occurs on the page
+}
+
+// TokenExports holds the design tokens rendered for different tool chains.
+type TokenExports struct {
+	W3C      string `json:"w3c"`      // W3C Design Tokens Community Group JSON
+	CSS      string `json:"css"`      // CSS custom properties on :root
+	Tailwind string `json:"tailwind"` // tailwind.config.js theme.extend snippet
+}
@@ -3,25 +3,50 @@ package types
 type Report struct {
 	Title             string
 	URL               string
+	AutoScroll        *AutoScrollResult `json:"autoScroll,omitempty"`
 	Navigation        map[string]interface{}
-	MobileFriendly    bool
-	Readability       string
+	MobileFriendly    *MobileFriendliness
+	Readability       *ReadabilityMetrics
+	Headings          *HeadingOutline `json:"headings,omitempty"`
 	Screenshots       map[string]string
-	ColorUsage        map[string]interface{}
+	Thumbnails        map[string]string `json:"thumbnails,omitempty"`
+	ScreenshotClips   map[string]Rect   `json:"screenshotClips,omitempty"`
+	ColorUsage        *ColorPalette
 	FontUsage         map[string]interface{}
-	SEO               map[string]interface{}
+	Typography        *TypographyAudit `json:"typography,omitempty"`
+	Spacing           *SpacingAudit    `json:"spacing,omitempty"`
+	DesignTokens      *DesignTokens    `json:"designTokens,omitempty"`
+	SEO               *SEOAudit
+	StructuredData    *StructuredDataAudit    `json:"structuredData,omitempty"`
+	Images            *ImageAudit             `json:"images,omitempty"`
+	Accessibility     *AccessibilityAudit     `json:"accessibility,omitempty"`
+	Contrast          *ContrastAudit          `json:"contrast,omitempty"`
+	Keyboard          *KeyboardAudit          `json:"keyboard,omitempty"`
+	TapTargets        *TapTargetAudit         `json:"tapTargets,omitempty"`
+	Sections          []*SectionAnalysis      `json:"sections,omitempty"`
+	CTA               *CTAAudit               `json:"cta,omitempty"`
+	AboveTheFold      []*FoldAnalysis         `json:"aboveTheFold,omitempty"`
+	Forms             *FormAudit              `json:"forms,omitempty"`
+	VisualRegression  *VisualRegression       `json:"visualRegression,omitempty"`
+	Annotated         []*AnnotatedScreenshot  `json:"annotated,omitempty"`
 	GeminiAnalysis    *GeminiUXAnalysisResult `json:"geminiAnalysis,omitempty"`
 	AiAnalysis        *GeminiUXAnalysisResult `json:"aiAnalysis,omitempty"`
 	PageSpeedInsights *PageSpeedInsights      `json:"pageSpeedInsights,omitempty"`
+	Filmstrip         *Filmstrip              `json:"filmstrip,omitempty"`
 }
 
+// SectionAnalysis holds the font, CTA and color results for one segment of the page.
 type SectionAnalysis struct {
-	Name        string
-	FontSizes   map[string]int
-	CtaStyles   map[string]string
-	ColorScheme map[string]string
-	Score       int
-	Details     string
+	Name        string            // Human readable label, e.g. "Hero" or "Main: Pricing"
+	Kind        string            // header, hero, main, repeated or footer
+	Selector    string            // CSS selector of the section element
+	Rect        Rect              // Page-relative bounding box
+	FontSizes   map[string]int    // Text elements per computed font size
+	CtaStyles   map[string]string // Computed style of the most prominent button
+	ColorScheme map[string]int    // Text and background colors with their usage count
+	Score       int               // Heuristic 0-60 score from the checks above
+	Details     string            // Plain-text summary of the results
+	Screenshot  string            // Base64 PNG of the section, when screenshots are enabled
 }
 
 type PageSpeedInsights struct {
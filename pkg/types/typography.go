@@ -0,0 +1,61 @@
+package types
+
+// TypographyAudit summarizes the type system of the page: the scale, how many sizes,
+// weights and families are in use, body text readability and web font loading.
+type TypographyAudit struct {
+	BaseSize         float64           `json:"baseSize"`         // Most common body font size in CSS pixels
+	Scale            []TypeScaleStep   `json:"scale"`            // Distinct font sizes in use, smallest first
+	ScaleRatio       float64           `json:"scaleRatio"`       // Best fitting modular ratio
+	ScaleRatioName   string            `json:"scaleRatioName"`   // Name of that ratio, e.g. "Major Third"
+	ScaleFitError    float64           `json:"scaleFitError"`    // Mean relative deviation (0-1) of the sizes from the ratio
+	FollowsRatio     bool              `json:"followsRatio"`     // Whether the sizes follow the ratio closely
+	DistinctSizes    int               `json:"distinctSizes"`    // Number of distinct font sizes
+	DistinctWeights  int               `json:"distinctWeights"`  // Number of distinct font weights
+	DistinctFamilies int               `json:"distinctFamilies"` // Number of distinct font families
+	Weights          map[string]int    `json:"weights"`          // Characters set per font weight
+	Families         map[string]int    `json:"families"`         // Characters set per primary font family
+	BodyText         BodyTextMetrics   `json:"bodyText"`         // Line height and line length of body paragraphs
+	FontFaces        []FontFaceInfo    `json:"fontFaces"`        // Declared web fonts
+	WebFontFiles     int               `json:"webFontFiles"`     // Font files downloaded by the page
+	WebFontBytes     int64             `json:"webFontBytes"`     // Transfer size of those files
+	PreloadedFonts   int               `json:"preloadedFonts"`   // <link rel="preload" as="font"> hints
+	FOITRisk         string            `json:"foitRisk"`         // low, medium or high risk of invisible text while fonts load
+	Issues           []TypographyIssue `json:"issues"`           // Findings, including limits that were exceeded
+}
+
+// TypeScaleStep is one font size of the type scale.
+type TypeScaleStep struct {
+	Size       float64  `json:"size"`       // Font size in CSS pixels
+	Step       int      `json:"step"`       // Step on the modular scale relative to the base size
+	Expected   float64  `json:"expected"`   // Size the ratio predicts for that step
+	Elements   int      `json:"elements"`   // Elements using this size
+	Characters int      `json:"characters"` // Characters set in this size
+	Tags       []string `json:"tags"`       // Tags using this size
+}
+
+// BodyTextMetrics describes the readability of body paragraphs.
+type BodyTextMetrics struct {
+	Paragraphs          int     `json:"paragraphs"`          // Paragraphs measured
+	LineHeight          float64 `json:"lineHeight"`          // Median line-height as a multiple of the font size
+	CharsPerLine        float64 `json:"charsPerLine"`        // Median characters per line
+	TightLineHeight     int     `json:"tightLineHeight"`     // Paragraphs with line-height below 1.4
+	LongLines           int     `json:"longLines"`           // Paragraphs with more than 80 characters per line
+	ShortLines          int     `json:"shortLines"`          // Paragraphs with fewer than 45 characters per line
+	LongestLineSelector string  `json:"longestLineSelector"` // Paragraph with the longest lines
+}
+
+// FontFaceInfo describes one @font-face declaration.
+type FontFaceInfo struct {
+	Family  string `json:"family"`  // font-family name
+	Weight  string `json:"weight"`  // font-weight descriptor
+	Style   string `json:"style"`   // font-style descriptor
+	Display string `json:"display"` // font-display descriptor (auto if not set)
+	Status  string `json:"status"`  // unloaded, loading, loaded or error
+}
+
+// TypographyIssue is a single typography finding.
+type TypographyIssue struct {
+	Type     string `json:"type"`     // Kind of issue, e.g. "too-many-sizes" or "font-display"
+	Message  string `json:"message"`  // Explanation of the problem
+	Selector string `json:"selector"` // CSS selector of the element, if the issue is about one element
+}
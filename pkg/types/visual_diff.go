@@ -0,0 +1,26 @@
+package types
+
+// VisualRegression compares the screenshots of a report with those of a baseline report.
+type VisualRegression struct {
+	Baseline           string       `json:"baseline"`           // ID or description of the baseline report
+	BaselineURL        string       `json:"baselineUrl"`        // URL the baseline was captured from
+	Threshold          float64      `json:"threshold"`          // Perceptual color distance (0-1) tolerated per pixel
+	Changed            bool         `json:"changed"`            // Whether any screenshot has changed regions
+	MismatchPercentage float64      `json:"mismatchPercentage"` // Highest perceptual mismatch across screenshots
+	Diffs              []VisualDiff `json:"diffs"`              // One entry per screenshot present in both reports
+	Missing            []string     `json:"missing"`            // Screenshots present in only one of the reports
+}
+
+// VisualDiff is the comparison of one screenshot with its baseline.
+type VisualDiff struct {
+	Name               string  `json:"name"`               // Screenshot name, e.g. "Desktop"
+	Width              int     `json:"width"`              // Width of the compared area in image pixels
+	Height             int     `json:"height"`             // Height of the compared area in image pixels
+	SizeChanged        bool    `json:"sizeChanged"`        // Whether the two images differ in size
+	PixelMismatch      float64 `json:"pixelMismatch"`      // Percentage of compared pixels that differ at all
+	MismatchPercentage float64 `json:"mismatchPercentage"` // Percentage of compared pixels that differ perceptibly
+	Similarity         float64 `json:"similarity"`         // Mean structural similarity (SSIM), 1 for identical images
+	ChangedRegions     []Rect  `json:"changedRegions"`     // Bounding boxes of changed areas in image pixels, largest first
+	IgnoredRegions     []Rect  `json:"ignoredRegions"`     // Areas excluded from the comparison in image pixels
+	DiffImage          string  `json:"diffImage"`          // Base64 PNG: faded current image, changes in red
+}
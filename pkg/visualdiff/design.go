@@ -0,0 +1,251 @@
+package visualdiff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+
+	"uxlyze/analyzer/pkg/types"
+)
+
+// alignWidth is the width both images are downsampled to for the coarse alignment search.
+const alignWidth = 256
+
+// maxAlignShift is the largest offset in reference pixels searched when aligning a capture
+// with its reference design.
+const maxAlignShift = 32
+
+// alignSamples caps the pixels compared per candidate offset at full resolution.
+const alignSamples = 100000
+
+// heatCell is the grid size in pixels the deviation is averaged over for the heatmap.
+const heatCell = 8
+
+var (
+	heatLow  = color.RGBA{250, 204, 21, 255}
+	heatHigh = color.RGBA{220, 38, 38, 255}
+)
+
+// CompareDesign compares a capture of the live page with a reference design export. The
+// capture is scaled to the width of the reference and shifted by the offset that lines it
+// up best, then compared pixel by pixel. The deviation is averaged per cell into a heatmap
+// drawn over the capture, and changed areas are reported as regions scored by how far they
+// drift from the design.
+func CompareDesign(reference, capture image.Image, opts Options) (*types.DesignComparison, *image.RGBA) {
+	ref, shot := toRGBA(reference), toRGBA(capture)
+	width, height := ref.Bounds().Dx(), ref.Bounds().Dy()
+	if w := shot.Bounds().Dx(); w != width && w > 0 {
+		shot = resize(shot, width, max(1, shot.Bounds().Dy()*width/w))
+	}
+
+	dx, dy := align(ref, shot)
+	aligned := shift(shot, dx, dy, width, height)
+	opts.IgnoreRegions = append(uncovered(dx, dy, width, height), opts.IgnoreRegions...)
+	diff, _ := CompareImages(ref, aligned, opts)
+
+	comparison := &types.DesignComparison{
+		Width:              width,
+		Height:             height,
+		OffsetX:            dx,
+		OffsetY:            dy,
+		Score:              int(math.Round(math.Max(0, diff.Similarity) * 100)),
+		Similarity:         diff.Similarity,
+		MismatchPercentage: diff.MismatchPercentage,
+		Regions:            []types.DesignRegion{},
+	}
+
+	cols, rows := (width+heatCell-1)/heatCell, (height+heatCell-1)/heatCell
+	sums := make([]float64, cols*rows)
+	counts := make([]int, cols*rows)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if ignored(opts.IgnoreRegions, x, y) {
+				continue
+			}
+			cell := (y/heatCell)*cols + x/heatCell
+			sums[cell] += math.Sqrt(yiqDelta(ref.RGBAAt(x, y), aligned.RGBAAt(x, y)) / maxYIQDelta)
+			counts[cell]++
+		}
+	}
+
+	heatmap := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cell := (y/heatCell)*cols + x/heatCell
+			base := washed(aligned.RGBAAt(x, y))
+			if counts[cell] == 0 {
+				heatmap.SetRGBA(x, y, tint(base, diffIgnored))
+				continue
+			}
+			heatmap.SetRGBA(x, y, heat(base, sums[cell]/float64(counts[cell]), opts.threshold()))
+		}
+	}
+
+	for _, r := range diff.ChangedRegions {
+		deviation := regionDeviation(r, sums, counts, cols)
+		comparison.Regions = append(comparison.Regions, types.DesignRegion{
+			Rect:      r,
+			Score:     int(math.Round(100 * (1 - math.Min(1, 2*deviation)))),
+			Deviation: round2(deviation),
+		})
+		outline(heatmap, r, diffChanged)
+	}
+	sort.SliceStable(comparison.Regions, func(i, j int) bool {
+		return comparison.Regions[i].Score < comparison.Regions[j].Score
+	})
+	return comparison, heatmap
+}
+
+// align finds the offset that best lines the capture up with the reference: a coarse search
+// on downsampled images, refined at full resolution around the best coarse match.
+func align(ref, shot *image.RGBA) (int, int) {
+	factor := max(1, ref.Bounds().Dx()/alignWidth)
+	smallRef := resize(ref, max(1, ref.Bounds().Dx()/factor), max(1, ref.Bounds().Dy()/factor))
+	smallShot := resize(shot, max(1, shot.Bounds().Dx()/factor), max(1, shot.Bounds().Dy()/factor))
+
+	reach := max(1, maxAlignShift/factor)
+	cx, cy := bestOffset(smallRef, smallShot, 0, 0, reach, 1)
+	if factor == 1 {
+		return cx, cy
+	}
+	return bestOffset(ref, shot, cx*factor, cy*factor, factor, 0)
+}
+
+// bestOffset searches the offsets within reach of (ox, oy) for the one with the lowest mean
+// luma difference between the reference and the shifted capture. A step of 0 samples the
+// overlap evenly up to alignSamples pixels.
+func bestOffset(ref, shot *image.RGBA, ox, oy, reach, step int) (int, int) {
+	width := min(ref.Bounds().Dx(), shot.Bounds().Dx())
+	height := min(ref.Bounds().Dy(), shot.Bounds().Dy())
+	if step == 0 {
+		step = max(1, int(math.Sqrt(float64(width*height)/alignSamples)))
+	}
+
+	bestX, bestY, bestCost := ox, oy, math.Inf(1)
+	for dy := oy - reach; dy <= oy+reach; dy++ {
+		for dx := ox - reach; dx <= ox+reach; dx++ {
+			// Only offsets that keep most of the images overlapping are meaningful
+			if abs(dx)*2 >= width || abs(dy)*2 >= height {
+				continue
+			}
+			total, n := 0.0, 0
+			for y := max(0, dy); y < height && y-dy < height; y += step {
+				for x := max(0, dx); x < width && x-dx < width; x += step {
+					lr, _, _ := yiq(ref.RGBAAt(x, y))
+					ls, _, _ := yiq(shot.RGBAAt(x-dx, y-dy))
+					total += math.Abs(lr - ls)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			// Prefer the smaller shift when costs tie, e.g. on blank areas
+			if cost := total / float64(n); cost < bestCost-1e-9 || (cost < bestCost+1e-9 && abs(dx)+abs(dy) < abs(bestX)+abs(bestY)) {
+				bestX, bestY, bestCost = dx, dy, cost
+			}
+		}
+	}
+	return bestX, bestY
+}
+
+// shift moves an image by (dx, dy) onto a white canvas of the given size.
+func shift(img *image.RGBA, dx, dy, width, height int) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(out, out.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(out, img.Bounds().Add(image.Pt(dx, dy)), img, image.Point{}, draw.Src)
+	return out
+}
+
+// uncovered returns the edges of the canvas the shifted capture leaves blank.
+func uncovered(dx, dy, width, height int) []types.Rect {
+	var edges []types.Rect
+	switch {
+	case dx > 0:
+		edges = append(edges, types.Rect{Width: float64(dx), Height: float64(height)})
+	case dx < 0:
+		edges = append(edges, types.Rect{X: float64(width + dx), Width: float64(-dx), Height: float64(height)})
+	}
+	switch {
+	case dy > 0:
+		edges = append(edges, types.Rect{Width: float64(width), Height: float64(dy)})
+	case dy < 0:
+		edges = append(edges, types.Rect{Y: float64(height + dy), Width: float64(width), Height: float64(-dy)})
+	}
+	return edges
+}
+
+// resize scales an image by averaging the source pixels covered by each target pixel.
+func resize(img *image.RGBA, width, height int) *image.RGBA {
+	sw, sh := img.Bounds().Dx(), img.Bounds().Dy()
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max(y0+1, (y+1)*sh/height)
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max(x0+1, (x+1)*sw/width)
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					p := img.RGBAAt(sx, sy)
+					r, g, b, a = r+int(p.R), g+int(p.G), b+int(p.B), a+int(p.A)
+					n++
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+	return out
+}
+
+// regionDeviation averages the heat cells a region covers.
+func regionDeviation(r types.Rect, sums []float64, counts []int, cols int) float64 {
+	total, n := 0.0, 0
+	for cy := int(r.Y) / heatCell; cy*heatCell < int(r.Y+r.Height); cy++ {
+		for cx := int(r.X) / heatCell; cx*heatCell < int(r.X+r.Width); cx++ {
+			cell := cy*cols + cx
+			if cell < len(sums) {
+				total += sums[cell]
+				n += counts[cell]
+			}
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / float64(n)
+}
+
+// washed lightens a pixel so the heatmap colors stand out while the page stays readable.
+func washed(c color.RGBA) color.RGBA {
+	mix := func(v uint8) uint8 { return uint8(128 + uint16(v)/2) }
+	return color.RGBA{mix(c.R), mix(c.G), mix(c.B), 255}
+}
+
+// heat blends a heatmap color over a pixel. Cells below half the threshold stay clear;
+// above it the color runs from yellow to red and grows more opaque with the deviation.
+func heat(base color.RGBA, deviation, threshold float64) color.RGBA {
+	if deviation < threshold/2 {
+		return base
+	}
+	t := math.Min(1, deviation*2)
+	alpha := math.Min(0.75, 0.25+deviation)
+	lerp := func(a, b uint8, t float64) float64 { return float64(a) + (float64(b)-float64(a))*t }
+	mix := func(v uint8, h float64) uint8 { return uint8(float64(v)*(1-alpha) + h*alpha) }
+	return color.RGBA{
+		mix(base.R, lerp(heatLow.R, heatHigh.R, t)),
+		mix(base.G, lerp(heatLow.G, heatHigh.G, t)),
+		mix(base.B, lerp(heatLow.B, heatHigh.B, t)),
+		255,
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
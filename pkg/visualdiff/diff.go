@@ -0,0 +1,482 @@
+package visualdiff
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"strings"
+
+	// Baseline images may be JPEG exports
+	_ "image/jpeg"
+
+	"uxlyze/analyzer/pkg/types"
+
+	// Screenshots may be captured as WebP
+	_ "golang.org/x/image/webp"
+)
+
+// DefaultThreshold is the perceptual color distance (0-1) below which two pixels count as
+// equal. It matches the default of pixelmatch.
+const DefaultThreshold = 0.1
+
+// regionCell is the grid size in pixels used to cluster changed pixels into regions.
+const regionCell = 16
+
+// minRegionPixels drops regions with fewer changed pixels as rendering noise.
+const minRegionPixels = 4
+
+// maxRegions caps the changed regions reported per image.
+const maxRegions = 50
+
+// ssimWindow is the window size for the structural similarity score.
+const ssimWindow = 8
+
+// maxYIQDelta is the largest possible YIQ distance between two colors.
+const maxYIQDelta = 35215
+
+var (
+	diffChanged    = color.RGBA{220, 38, 38, 255}
+	diffMinor      = color.RGBA{245, 158, 11, 255}
+	diffOutOfBound = color.RGBA{168, 85, 247, 255}
+	diffIgnored    = color.RGBA{79, 70, 229, 255}
+)
+
+// Options configure a comparison.
+type Options struct {
+	Threshold     float64      // Perceptual color distance (0-1) tolerated per pixel; DefaultThreshold when 0
+	IgnoreRegions []types.Rect // Areas in image pixels to exclude, e.g. carousels, ads or timestamps
+}
+
+func (o Options) threshold() float64 {
+	if o.Threshold <= 0 {
+		return DefaultThreshold
+	}
+	return o.Threshold
+}
+
+// ReportOptions configure a comparison of two reports. Ignore regions are given in page
+// CSS pixels, so the same region works for screenshots taken at any device scale factor.
+type ReportOptions struct {
+	Threshold     float64                 // Perceptual color distance (0-1) tolerated per pixel; DefaultThreshold when 0
+	IgnoreRegions map[string][]types.Rect // Areas in CSS pixels to exclude, keyed by screenshot name
+}
+
+// CompareImages diffs current against baseline pixel by pixel. Pixels are compared exactly
+// and by their perceived color distance in YIQ space; only perceptible differences count as
+// mismatches and make up the changed regions. Images of different sizes are compared over
+// the larger size, with the area covered by only one image counted as changed.
+func CompareImages(baseline, current image.Image, opts Options) (*types.VisualDiff, *image.RGBA) {
+	b, c := toRGBA(baseline), toRGBA(current)
+	width := max(b.Bounds().Dx(), c.Bounds().Dx())
+	height := max(b.Bounds().Dy(), c.Bounds().Dy())
+
+	result := &types.VisualDiff{
+		Width:          width,
+		Height:         height,
+		SizeChanged:    b.Bounds().Size() != c.Bounds().Size(),
+		ChangedRegions: []types.Rect{},
+		IgnoredRegions: opts.IgnoreRegions,
+	}
+	if result.IgnoredRegions == nil {
+		result.IgnoredRegions = []types.Rect{}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	maxDelta := maxYIQDelta * opts.threshold() * opts.threshold()
+	cols, rows := (width+regionCell-1)/regionCell, (height+regionCell-1)/regionCell
+	cells := make([]int, cols*rows)
+
+	compared, differing, mismatched := 0, 0, 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if ignored(opts.IgnoreRegions, x, y) {
+				out.SetRGBA(x, y, tint(pixelAt(c, x, y), diffIgnored))
+				continue
+			}
+			compared++
+
+			inB := image.Pt(x, y).In(b.Bounds())
+			inC := image.Pt(x, y).In(c.Bounds())
+			if !inB || !inC {
+				differing++
+				mismatched++
+				cells[(y/regionCell)*cols+x/regionCell]++
+				out.SetRGBA(x, y, diffOutOfBound)
+				continue
+			}
+
+			pb, pc := b.RGBAAt(x, y), c.RGBAAt(x, y)
+			switch {
+			case pb == pc:
+				out.SetRGBA(x, y, faded(pc))
+			case yiqDelta(pb, pc) > maxDelta:
+				differing++
+				mismatched++
+				cells[(y/regionCell)*cols+x/regionCell]++
+				out.SetRGBA(x, y, diffChanged)
+			default:
+				differing++
+				out.SetRGBA(x, y, diffMinor)
+			}
+		}
+	}
+
+	if compared > 0 {
+		result.PixelMismatch = round2(float64(differing) / float64(compared) * 100)
+		result.MismatchPercentage = round2(float64(mismatched) / float64(compared) * 100)
+	}
+	result.Similarity = round2(meanSSIM(b, c, opts.IgnoreRegions))
+	result.ChangedRegions = changedRegions(cells, cols, rows, width, height)
+	for _, r := range result.ChangedRegions {
+		outline(out, r, diffChanged)
+	}
+	return result, out
+}
+
+// CompareBase64 decodes two base64 encoded images, compares them and returns the result
+// with the diff image attached as a base64 PNG.
+func CompareBase64(baseline, current string, opts Options) (*types.VisualDiff, error) {
+	b, err := DecodeBase64Image(baseline)
+	if err != nil {
+		return nil, fmt.Errorf("decoding baseline image: %w", err)
+	}
+	c, err := DecodeBase64Image(current)
+	if err != nil {
+		return nil, fmt.Errorf("decoding current image: %w", err)
+	}
+
+	result, diff := CompareImages(b, c, opts)
+	result.DiffImage, err = EncodePNG(diff)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CompareReports diffs every screenshot the two reports have in common, including the
+// above-the-fold screenshots of each device.
+func CompareReports(baseline, current *types.Report, opts ReportOptions) *types.VisualRegression {
+	regression := &types.VisualRegression{
+		BaselineURL: baseline.URL,
+		Threshold:   Options{Threshold: opts.Threshold}.threshold(),
+		Diffs:       []types.VisualDiff{},
+		Missing:     []string{},
+	}
+
+	before, after := reportScreenshots(baseline), reportScreenshots(current)
+	clips := screenshotClips(current)
+	var names []string
+	for name := range after {
+		names = append(names, name)
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, c := before[name], after[name]
+		if b == "" || c == "" {
+			regression.Missing = append(regression.Missing, name)
+			continue
+		}
+		diff, err := compareScreenshot(b, c, clips[name], opts.IgnoreRegions[name], opts.Threshold)
+		if err != nil {
+			regression.Missing = append(regression.Missing, name)
+			continue
+		}
+		diff.Name = name
+		regression.Diffs = append(regression.Diffs, *diff)
+		regression.Changed = regression.Changed || len(diff.ChangedRegions) > 0
+		regression.MismatchPercentage = math.Max(regression.MismatchPercentage, diff.MismatchPercentage)
+	}
+	return regression
+}
+
+// reportScreenshots collects the screenshots of a report by name.
+func reportScreenshots(report *types.Report) map[string]string {
+	shots := make(map[string]string)
+	for name, data := range report.Screenshots {
+		shots[name] = data
+	}
+	for _, fold := range report.AboveTheFold {
+		if fold != nil {
+			shots["AboveTheFold "+fold.Device] = fold.Screenshot
+		}
+	}
+	return shots
+}
+
+// screenshotClips collects the page area each screenshot of a report shows. The fold
+// screenshots show the first viewport of their device.
+func screenshotClips(report *types.Report) map[string]types.Rect {
+	clips := make(map[string]types.Rect)
+	for name, clip := range report.ScreenshotClips {
+		clips[name] = clip
+	}
+	for _, fold := range report.AboveTheFold {
+		if fold != nil {
+			clips["AboveTheFold "+fold.Device] = types.Rect{Width: float64(fold.Width), Height: float64(fold.Height)}
+		}
+	}
+	return clips
+}
+
+// compareScreenshot decodes and compares two screenshots of a report, after mapping the
+// ignore regions onto the pixels of the current screenshot.
+func compareScreenshot(baseline, current string, clip types.Rect, ignore []types.Rect, threshold float64) (*types.VisualDiff, error) {
+	b, err := DecodeBase64Image(baseline)
+	if err != nil {
+		return nil, fmt.Errorf("decoding baseline image: %w", err)
+	}
+	c, err := DecodeBase64Image(current)
+	if err != nil {
+		return nil, fmt.Errorf("decoding current image: %w", err)
+	}
+
+	opts := Options{Threshold: threshold, IgnoreRegions: scaleRegions(ignore, clip, c.Bounds().Dx())}
+	result, diff := CompareImages(b, c, opts)
+	result.DiffImage, err = EncodePNG(diff)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scaleRegions maps page regions in CSS pixels onto a screenshot of clip that is imageWidth
+// pixels wide. Without a known clip, CSS and image pixels are taken to be the same.
+func scaleRegions(regions []types.Rect, clip types.Rect, imageWidth int) []types.Rect {
+	scale := 1.0
+	if clip.Width > 0 {
+		scale = float64(imageWidth) / clip.Width
+	}
+	scaled := make([]types.Rect, 0, len(regions))
+	for _, r := range regions {
+		scaled = append(scaled, types.Rect{
+			X:      (r.X - clip.X) * scale,
+			Y:      (r.Y - clip.Y) * scale,
+			Width:  r.Width * scale,
+			Height: r.Height * scale,
+		})
+	}
+	return scaled
+}
+
+// EncodePNG encodes an image as a base64 PNG.
+func EncodePNG(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeBase64Image decodes a base64 PNG, JPEG or WebP, with or without a data URI prefix.
+func DecodeBase64Image(data string) (image.Image, error) {
+	if strings.HasPrefix(data, "data:") {
+		if i := strings.IndexByte(data, ','); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	return img, err
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+func pixelAt(img *image.RGBA, x, y int) color.RGBA {
+	if !image.Pt(x, y).In(img.Bounds()) {
+		return color.RGBA{255, 255, 255, 255}
+	}
+	return img.RGBAAt(x, y)
+}
+
+func ignored(regions []types.Rect, x, y int) bool {
+	px, py := float64(x), float64(y)
+	for _, r := range regions {
+		if px >= r.X && px < r.X+r.Width && py >= r.Y && py < r.Y+r.Height {
+			return true
+		}
+	}
+	return false
+}
+
+// yiq converts a color, blended over white, to the YIQ space used by pixelmatch.
+func yiq(c color.RGBA) (y, i, q float64) {
+	a := float64(c.A) / 255
+	r := 255 + (float64(c.R)-255)*a
+	g := 255 + (float64(c.G)-255)*a
+	b := 255 + (float64(c.B)-255)*a
+	y = r*0.29889531 + g*0.58662247 + b*0.11448223
+	i = r*0.59597799 - g*0.27417610 - b*0.32180189
+	q = r*0.21147017 - g*0.52261711 + b*0.31114694
+	return y, i, q
+}
+
+// yiqDelta is the perceived distance between two colors (Kotsarenko and Ramos, 2010).
+func yiqDelta(a, b color.RGBA) float64 {
+	y1, i1, q1 := yiq(a)
+	y2, i2, q2 := yiq(b)
+	dy, di, dq := y1-y2, i1-i2, q1-q2
+	return 0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq
+}
+
+// faded renders an unchanged pixel as light gray so changes stand out.
+func faded(c color.RGBA) color.RGBA {
+	y, _, _ := yiq(c)
+	v := uint8(255 + (y-255)*0.1)
+	return color.RGBA{v, v, v, 255}
+}
+
+// tint mixes a pixel with a highlight color.
+func tint(c, highlight color.RGBA) color.RGBA {
+	mix := func(a, b uint8) uint8 { return uint8((uint16(a) + uint16(b)*3) / 4) }
+	return color.RGBA{mix(c.R, highlight.R), mix(c.G, highlight.G), mix(c.B, highlight.B), 255}
+}
+
+// outline draws a 2px frame around a region.
+func outline(img *image.RGBA, r types.Rect, c color.RGBA) {
+	x0, y0 := int(r.X), int(r.Y)
+	x1, y1 := x0+int(r.Width)-1, y0+int(r.Height)-1
+	for t := 0; t < 2; t++ {
+		for x := x0; x <= x1; x++ {
+			img.SetRGBA(x, y0+t, c)
+			img.SetRGBA(x, y1-t, c)
+		}
+		for y := y0; y <= y1; y++ {
+			img.SetRGBA(x0+t, y, c)
+			img.SetRGBA(x1-t, y, c)
+		}
+	}
+}
+
+// meanSSIM averages the structural similarity of the luma channel over non-overlapping
+// windows in the area both images cover, skipping windows that touch an ignored region.
+func meanSSIM(b, c *image.RGBA, ignore []types.Rect) float64 {
+	const c1, c2 = (0.01 * 255) * (0.01 * 255), (0.03 * 255) * (0.03 * 255)
+	width := min(b.Bounds().Dx(), c.Bounds().Dx())
+	height := min(b.Bounds().Dy(), c.Bounds().Dy())
+
+	total, windows := 0.0, 0
+	for wy := 0; wy+ssimWindow <= height; wy += ssimWindow {
+		for wx := 0; wx+ssimWindow <= width; wx += ssimWindow {
+			if windowIgnored(ignore, wx, wy) {
+				continue
+			}
+			var sumB, sumC, sumBB, sumCC, sumBC float64
+			for y := wy; y < wy+ssimWindow; y++ {
+				for x := wx; x < wx+ssimWindow; x++ {
+					lb, _, _ := yiq(b.RGBAAt(x, y))
+					lc, _, _ := yiq(c.RGBAAt(x, y))
+					sumB += lb
+					sumC += lc
+					sumBB += lb * lb
+					sumCC += lc * lc
+					sumBC += lb * lc
+				}
+			}
+			n := float64(ssimWindow * ssimWindow)
+			muB, muC := sumB/n, sumC/n
+			varB, varC := sumBB/n-muB*muB, sumCC/n-muC*muC
+			cov := sumBC/n - muB*muC
+			total += ((2*muB*muC + c1) * (2*cov + c2)) / ((muB*muB + muC*muC + c1) * (varB + varC + c2))
+			windows++
+		}
+	}
+	if windows == 0 {
+		return 1
+	}
+	return total / float64(windows)
+}
+
+func windowIgnored(regions []types.Rect, wx, wy int) bool {
+	for y := wy; y < wy+ssimWindow; y += ssimWindow - 1 {
+		for x := wx; x < wx+ssimWindow; x += ssimWindow - 1 {
+			if ignored(regions, x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changedRegions joins neighbouring grid cells with mismatches into bounding boxes.
+func changedRegions(cells []int, cols, rows, width, height int) []types.Rect {
+	type region struct {
+		minX, minY, maxX, maxY, pixels int
+	}
+	var regions []region
+	seen := make([]bool, len(cells))
+	for start := range cells {
+		if cells[start] == 0 || seen[start] {
+			continue
+		}
+		r := region{minX: cols, minY: rows, maxX: -1, maxY: -1}
+		stack := []int{start}
+		seen[start] = true
+		for len(stack) > 0 {
+			cell := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			cx, cy := cell%cols, cell/cols
+			r.minX, r.minY = min(r.minX, cx), min(r.minY, cy)
+			r.maxX, r.maxY = max(r.maxX, cx), max(r.maxY, cy)
+			r.pixels += cells[cell]
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := cx+dx, cy+dy
+					if nx < 0 || ny < 0 || nx >= cols || ny >= rows {
+						continue
+					}
+					next := ny*cols + nx
+					if cells[next] > 0 && !seen[next] {
+						seen[next] = true
+						stack = append(stack, next)
+					}
+				}
+			}
+		}
+		if r.pixels >= minRegionPixels {
+			regions = append(regions, r)
+		}
+	}
+
+	rects := make([]types.Rect, 0, len(regions))
+	for _, r := range regions {
+		x, y := r.minX*regionCell, r.minY*regionCell
+		rects = append(rects, types.Rect{
+			X:      float64(x),
+			Y:      float64(y),
+			Width:  float64(min((r.maxX+1)*regionCell, width) - x),
+			Height: float64(min((r.maxY+1)*regionCell, height) - y),
+		})
+	}
+	sort.SliceStable(rects, func(i, j int) bool { return rects[i].Width*rects[i].Height > rects[j].Width*rects[j].Height })
+	if len(rects) > maxRegions {
+		rects = rects[:maxRegions]
+	}
+	return rects
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
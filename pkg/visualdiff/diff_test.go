@@ -0,0 +1,141 @@
+package visualdiff
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"uxlyze/analyzer/pkg/types"
+)
+
+// solid returns a w x h white image with the given rectangle filled in red.
+func solid(w, h int, changed image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{255, 255, 255, 255}
+			if image.Pt(x, y).In(changed) {
+				c = color.RGBA{255, 0, 0, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encode(t *testing.T, img image.Image) string {
+	t.Helper()
+	data, err := EncodePNG(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestCompareReportsScalesIgnoreRegions(t *testing.T) {
+	// A 2x screenshot of a 32px wide page whose CSS area (8, 8)-(24, 24) changed
+	baseline := &types.Report{Screenshots: map[string]string{"Desktop": encode(t, solid(64, 64, image.Rectangle{}))}}
+	current := &types.Report{
+		Screenshots:     map[string]string{"Desktop": encode(t, solid(64, 64, image.Rect(16, 16, 48, 48)))},
+		ScreenshotClips: map[string]types.Rect{"Desktop": {Width: 32, Height: 32}},
+	}
+
+	regression := CompareReports(baseline, current, ReportOptions{})
+	if !regression.Changed {
+		t.Fatal("expected a change without ignore regions")
+	}
+
+	regression = CompareReports(baseline, current, ReportOptions{
+		IgnoreRegions: map[string][]types.Rect{"Desktop": {{X: 8, Y: 8, Width: 16, Height: 16}}},
+	})
+	if len(regression.Diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1 (missing: %v)", len(regression.Diffs), regression.Missing)
+	}
+	diff := regression.Diffs[0]
+	if regression.Changed || diff.MismatchPercentage != 0 {
+		t.Errorf("ignored change still reported: %+v", diff.ChangedRegions)
+	}
+	want := types.Rect{X: 16, Y: 16, Width: 32, Height: 32}
+	if len(diff.IgnoredRegions) != 1 || diff.IgnoredRegions[0] != want {
+		t.Errorf("ignored regions = %v, want [%v]", diff.IgnoredRegions, want)
+	}
+}
+
+// solidWebP encodes a w x h image of one color as a lossless WebP. Every prefix code has a
+// single symbol, so the pixels themselves take no bits.
+func solidWebP(w, h int, c color.NRGBA) string {
+	var data []byte
+	var acc uint64
+	var n uint
+	write := func(value uint64, bits uint) {
+		acc |= value << n
+		for n += bits; n >= 8; n -= 8 {
+			data = append(data, byte(acc))
+			acc >>= 8
+		}
+	}
+	symbol := func(v uint8) {
+		write(1, 1) // simple code
+		write(0, 1) // one symbol
+		write(1, 1) // 8-bit symbol
+		write(uint64(v), 8)
+	}
+
+	write(0x2f, 8) // VP8L signature
+	write(uint64(w-1), 14)
+	write(uint64(h-1), 14)
+	write(0, 1) // alpha unused
+	write(0, 3) // version
+	write(0, 1) // no transforms
+	write(0, 1) // no color cache
+	write(0, 1) // no meta prefix codes
+	symbol(c.G)
+	symbol(c.R)
+	symbol(c.B)
+	symbol(c.A)
+	symbol(0) // distance
+	if n > 0 {
+		data = append(data, byte(acc))
+	}
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+
+	riff := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00")
+	binary.LittleEndian.PutUint32(riff[4:], uint32(12+len(data)))
+	binary.LittleEndian.PutUint32(riff[16:], uint32(len(data)))
+	return base64.StdEncoding.EncodeToString(append(riff, data...))
+}
+
+func TestCompareReportsDecodesWebP(t *testing.T) {
+	white := solidWebP(32, 32, color.NRGBA{255, 255, 255, 255})
+	red := solidWebP(32, 32, color.NRGBA{255, 0, 0, 255})
+
+	baseline := &types.Report{Screenshots: map[string]string{"Desktop": white, "Mobile": white}}
+	current := &types.Report{Screenshots: map[string]string{"Desktop": white, "Mobile": red}}
+
+	regression := CompareReports(baseline, current, ReportOptions{})
+	if len(regression.Missing) != 0 {
+		t.Fatalf("screenshots not compared: %v", regression.Missing)
+	}
+	if len(regression.Diffs) != 2 {
+		t.Fatalf("got %d diffs, want 2", len(regression.Diffs))
+	}
+	for _, diff := range regression.Diffs {
+		switch diff.Name {
+		case "Desktop":
+			if diff.MismatchPercentage != 0 || len(diff.ChangedRegions) != 0 {
+				t.Errorf("Desktop: mismatch %v%%, want identical", diff.MismatchPercentage)
+			}
+		case "Mobile":
+			if diff.MismatchPercentage != 100 || len(diff.ChangedRegions) == 0 {
+				t.Errorf("Mobile: mismatch %v%%, want 100%%", diff.MismatchPercentage)
+			}
+		}
+	}
+	if !regression.Changed {
+		t.Error("regression not marked as changed")
+	}
+}